@@ -15,14 +15,22 @@
 package checker
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/toodofun/pulse/internal/checker/docker"
+	"github.com/toodofun/pulse/internal/checker/domain"
 	"github.com/toodofun/pulse/internal/checker/http"
+	"github.com/toodofun/pulse/internal/checker/kubernetes"
+	"github.com/toodofun/pulse/internal/checker/ldap"
+	"github.com/toodofun/pulse/internal/checker/ntp"
+	"github.com/toodofun/pulse/internal/checker/snmp"
 	"github.com/toodofun/pulse/internal/model"
 )
 
 type Checker interface {
-	Check(fields string) *model.Record
+	// Check 执行一次检查，ctx 被取消时应尽快返回
+	Check(ctx context.Context, fields string) *model.Record
 	Validate(fields string) error
 }
 
@@ -30,6 +38,18 @@ func GetChecker(t model.CheckerType) (Checker, error) {
 	switch t {
 	case http.CheckerTypeHTTP:
 		return &http.Checker{}, nil
+	case ldap.CheckerTypeLDAP:
+		return &ldap.Checker{}, nil
+	case ntp.CheckerTypeNTP:
+		return &ntp.Checker{}, nil
+	case domain.CheckerTypeDomain:
+		return &domain.Checker{}, nil
+	case docker.CheckerTypeDocker:
+		return &docker.Checker{}, nil
+	case kubernetes.CheckerTypeKubernetes:
+		return &kubernetes.Checker{}, nil
+	case snmp.CheckerTypeSNMP:
+		return &snmp.Checker{}, nil
 	default:
 		return nil, fmt.Errorf("unknown checker: %s", t)
 	}
@@ -0,0 +1,222 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mcuadros/go-defaults"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const (
+	CheckerTypeDocker model.CheckerType = "docker"
+
+	healthHealthy = "healthy"
+)
+
+type Checker struct {
+}
+
+type fields struct {
+	// Host 支持 unix:///var/run/docker.sock、tcp://host:2375、http(s)://host:port
+	Host               string `json:"host"               default:"unix:///var/run/docker.sock"`
+	Container          string `json:"container"`
+	APIVersion         string `json:"apiVersion"`
+	RequireHealthcheck bool   `json:"requireHealthcheck"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	Timeout            int    `json:"timeout"            default:"10"`
+}
+
+type containerState struct {
+	Status   string `json:"Status"`
+	Running  bool   `json:"Running"`
+	ExitCode int    `json:"ExitCode"`
+	Health   *struct {
+		Status        string `json:"Status"`
+		FailingStreak int    `json:"FailingStreak"`
+		Log           []struct {
+			ExitCode int    `json:"ExitCode"`
+			Output   string `json:"Output"`
+		} `json:"Log"`
+	} `json:"Health"`
+}
+
+type containerInspect struct {
+	Name  string         `json:"Name"`
+	State containerState `json:"State"`
+}
+
+func (c *Checker) Validate(fields string) error {
+	_, err := c.fromFields(fields)
+	return err
+}
+
+func (c *Checker) fromFields(fieldsStr string) (*fields, error) {
+	f := new(fields)
+	if err := json.Unmarshal([]byte(fieldsStr), &f); err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+	defaults.SetDefaults(f)
+
+	u, err := url.Parse(f.Host)
+	if err != nil {
+		return nil, errors.New("invalid param: host")
+	}
+	switch u.Scheme {
+	case "unix":
+		if u.Path == "" {
+			return nil, errors.New("host must contain the socket path, e.g. unix:///var/run/docker.sock")
+		}
+	case "tcp", "http", "https":
+		if u.Host == "" {
+			return nil, errors.New("host must contain an address, e.g. tcp://127.0.0.1:2375")
+		}
+	default:
+		return nil, errors.New("host scheme must be one of unix, tcp, http, https")
+	}
+
+	if f.Container == "" {
+		return nil, errors.New("container is required")
+	}
+	if f.APIVersion != "" && !strings.HasPrefix(f.APIVersion, "v") {
+		f.APIVersion = "v" + f.APIVersion
+	}
+	if f.Timeout <= 0 {
+		return nil, errors.New("timeout must be greater than 0")
+	}
+
+	return f, nil
+}
+
+func (c *Checker) Check(ctx context.Context, fieldStr string) *model.Record {
+	fs, err := c.fromFields(fieldStr)
+	if err != nil {
+		return &model.Record{
+			IsSuccess: false,
+			Message:   err.Error(),
+			MonitorAt: time.Now(),
+		}
+	}
+
+	start := time.Now()
+	record := &model.Record{
+		MonitorAt: start,
+	}
+
+	container, err := c.inspect(ctx, fs)
+	if err != nil {
+		record.IsSuccess = false
+		record.Message = err.Error()
+		return record
+	}
+	record.ResponseTime = time.Since(start).Milliseconds()
+
+	state := container.State
+	switch {
+	case !state.Running:
+		record.IsSuccess = false
+		record.Message = fmt.Sprintf("container is %s, exit code %d", state.Status, state.ExitCode)
+	case state.Health == nil && fs.RequireHealthcheck:
+		record.IsSuccess = false
+		record.Message = "container has no healthcheck"
+	case state.Health == nil:
+		record.IsSuccess = true
+		record.Message = "OK, running"
+	case state.Health.Status != healthHealthy:
+		record.IsSuccess = false
+		record.Message = fmt.Sprintf("container health is %s, failing streak %d", state.Health.Status, state.Health.FailingStreak)
+		if n := len(state.Health.Log); n > 0 {
+			record.Message = fmt.Sprintf("%s: %s", record.Message, strings.TrimSpace(state.Health.Log[n-1].Output))
+		}
+	default:
+		record.IsSuccess = true
+		record.Message = "OK, running and healthy"
+	}
+
+	return record
+}
+
+func (c *Checker) inspect(ctx context.Context, fs *fields) (*containerInspect, error) {
+	u, _ := url.Parse(fs.Host)
+	timeout := time.Duration(fs.Timeout) * time.Second
+
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: fs.InsecureSkipVerify},
+	}
+	base := url.URL{Scheme: "http", Host: u.Host}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: timeout}
+			return d.DialContext(ctx, "unix", socket)
+		}
+		base.Host = "docker"
+	case "https":
+		base.Scheme = "https"
+	}
+	defer transport.CloseIdleConnections()
+
+	client := http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+
+	path := "/containers/" + url.PathEscape(fs.Container) + "/json"
+	if fs.APIVersion != "" {
+		path = "/" + fs.APIVersion + path
+	}
+	base.Path = path
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("container %s not found", fs.Container)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var msg struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&msg)
+		return nil, fmt.Errorf("docker api returned status code %d: %s", resp.StatusCode, msg.Message)
+	}
+
+	res := new(containerInspect)
+	if err = json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, fmt.Errorf("failed to decode docker response: %w", err)
+	}
+
+	return res, nil
+}
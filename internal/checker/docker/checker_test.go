@@ -0,0 +1,73 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecker_Check(t *testing.T) {
+	containers := map[string]string{
+		"/containers/healthy/json":   `{"Name":"/healthy","State":{"Status":"running","Running":true,"Health":{"Status":"healthy"}}}`,
+		"/containers/unhealthy/json": `{"Name":"/unhealthy","State":{"Status":"running","Running":true,"Health":{"Status":"unhealthy","FailingStreak":3,"Log":[{"ExitCode":1,"Output":"boom"}]}}}`,
+		"/containers/plain/json":     `{"Name":"/plain","State":{"Status":"running","Running":true}}`,
+		"/containers/exited/json":    `{"Name":"/exited","State":{"Status":"exited","Running":false,"ExitCode":137}}`,
+	}
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen on unix socket: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := containers[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"No such container"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		container string
+		extra     string
+		want      bool
+	}{
+		{name: "healthy", container: "healthy", want: true},
+		{name: "unhealthy", container: "unhealthy", want: false},
+		{name: "no healthcheck", container: "plain", want: true},
+		{name: "no healthcheck required", container: "plain", extra: `,"requireHealthcheck":true`, want: false},
+		{name: "exited", container: "exited", want: false},
+		{name: "missing", container: "missing", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Checker{}
+			got := c.Check(context.Background(), fmt.Sprintf(`{"host":"unix://%s","container":%q%s}`, socket, tt.container, tt.extra))
+			if got.IsSuccess != tt.want {
+				t.Errorf("Check() IsSuccess = %v, want %v, message %q", got.IsSuccess, tt.want, got.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mcuadros/go-defaults"
+
+	"github.com/toodofun/pulse/internal/model"
+	"github.com/toodofun/pulse/internal/util"
+)
+
+const (
+	CheckerTypeDomain model.CheckerType = "domain"
+
+	eventExpiration = "expiration"
+)
+
+type Checker struct {
+}
+
+type fields struct {
+	Domain string `json:"domain"`
+	// Server 为 RDAP 服务地址，请求路径为 {server}/domain/{domain}
+	Server  string `json:"server"  default:"https://rdap.org"`
+	Days    int    `json:"days"    default:"30"`
+	Timeout int    `json:"timeout" default:"30"`
+}
+
+type rdapDomain struct {
+	LDHName string `json:"ldhName"`
+	Events  []struct {
+		Action string    `json:"eventAction"`
+		Date   time.Time `json:"eventDate"`
+	} `json:"events"`
+}
+
+func (c *Checker) Validate(fields string) error {
+	_, err := c.fromFields(fields)
+	return err
+}
+
+func (c *Checker) fromFields(fieldsStr string) (*fields, error) {
+	f := new(fields)
+	if err := json.Unmarshal([]byte(fieldsStr), &f); err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+	defaults.SetDefaults(f)
+
+	f.Domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(f.Domain)), ".")
+	if f.Domain == "" || !util.IsURL(f.Domain) || strings.Contains(f.Domain, "/") {
+		return nil, errors.New("domain is required and must be a registrable domain name")
+	}
+
+	u, err := url.Parse(f.Server)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("server must start with http:// or https://")
+	}
+	f.Server = strings.TrimSuffix(f.Server, "/")
+
+	if f.Days < 0 {
+		return nil, errors.New("days must not be negative")
+	}
+	if f.Timeout <= 0 {
+		return nil, errors.New("timeout must be greater than 0")
+	}
+
+	return f, nil
+}
+
+func (c *Checker) Check(ctx context.Context, fieldStr string) *model.Record {
+	fs, err := c.fromFields(fieldStr)
+	if err != nil {
+		return &model.Record{
+			IsSuccess: false,
+			Message:   err.Error(),
+			MonitorAt: time.Now(),
+		}
+	}
+
+	start := time.Now()
+	record := &model.Record{
+		MonitorAt: start,
+	}
+
+	expireAt, err := c.lookup(ctx, fs)
+	if err != nil {
+		record.IsSuccess = false
+		record.Message = err.Error()
+		return record
+	}
+	record.ResponseTime = time.Since(start).Milliseconds()
+
+	remaining := int(time.Until(expireAt).Hours() / 24)
+	record.IsSuccess = remaining >= fs.Days
+	record.Message = fmt.Sprintf("%d days remaining, expires at %s", remaining, expireAt.Format("2006-01-02"))
+	if !record.IsSuccess {
+		record.Message = fmt.Sprintf("%s, less than %d days", record.Message, fs.Days)
+	}
+
+	return record
+}
+
+func (c *Checker) lookup(ctx context.Context, fs *fields) (time.Time, error) {
+	client := http.Client{
+		Timeout: time.Duration(fs.Timeout) * time.Second,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/domain/%s", fs.Server, url.PathEscape(fs.Domain)), nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return time.Time{}, fmt.Errorf("domain %s not found in rdap", fs.Domain)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("rdap returned status code %d", resp.StatusCode)
+	}
+
+	var res rdapDomain
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode rdap response: %w", err)
+	}
+
+	for _, e := range res.Events {
+		if e.Action == eventExpiration {
+			return e.Date, nil
+		}
+	}
+
+	return time.Time{}, errors.New("rdap response has no expiration event")
+}
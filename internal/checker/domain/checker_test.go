@@ -0,0 +1,83 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChecker_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var days int
+		switch r.URL.Path {
+		case "/domain/fresh.com":
+			days = 200
+		case "/domain/stale.com":
+			days = 10
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		_, _ = fmt.Fprintf(w, `{"ldhName":"x","events":[{"eventAction":"registration","eventDate":"2000-01-01T00:00:00Z"},`+
+			`{"eventAction":"expiration","eventDate":"%s"}]}`,
+			time.Now().Add(time.Duration(days)*24*time.Hour+time.Hour).UTC().Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		domain  string
+		want    bool
+		message string
+	}{
+		{
+			name:    "enough days",
+			domain:  "fresh.com",
+			want:    true,
+			message: "200 days remaining",
+		},
+		{
+			name:    "expiring soon",
+			domain:  "stale.com",
+			want:    false,
+			message: "10 days remaining",
+		},
+		{
+			name:    "not found",
+			domain:  "missing.com",
+			want:    false,
+			message: "not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Checker{}
+			got := c.Check(context.Background(), fmt.Sprintf(`{"domain":%q,"server":%q,"days":30}`, tt.domain, server.URL))
+			if got.IsSuccess != tt.want {
+				t.Errorf("Check() IsSuccess = %v, want %v, message %q", got.IsSuccess, tt.want, got.Message)
+			}
+			if !strings.Contains(got.Message, tt.message) {
+				t.Errorf("Check() Message = %q, want containing %q", got.Message, tt.message)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -96,7 +97,7 @@ func (c *Checker) fromFields(fieldsStr string) (*fields, error) {
 	return f, nil
 }
 
-func (c *Checker) Check(fieldStr string) *model.Record {
+func (c *Checker) Check(ctx context.Context, fieldStr string) *model.Record {
 	fs, err := c.fromFields(fieldStr)
 	if err != nil {
 		return &model.Record{
@@ -132,6 +133,8 @@ func (c *Checker) Check(fieldStr string) *model.Record {
 		Body:   io.NopCloser(strings.NewReader(fs.Body)),
 	}
 
+	req = req.WithContext(ctx)
+
 	for k, v := range fs.Cookies {
 		req.AddCookie(&http.Cookie{
 			Name:  k,
@@ -0,0 +1,237 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/mcuadros/go-defaults"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/toodofun/pulse/internal/config"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const (
+	CheckerTypeKubernetes model.CheckerType = "kubernetes"
+
+	KindDeployment  = "deployment"
+	KindStatefulSet = "statefulset"
+	KindService     = "service"
+)
+
+type Checker struct {
+}
+
+type fields struct {
+	// Kubeconfig 为服务端 kubernetes.kubeconfigDir 目录下的文件名，为空时使用 in-cluster 配置
+	Kubeconfig string `json:"kubeconfig"`
+	Context    string `json:"context"`
+	Namespace  string `json:"namespace"  default:"default"`
+	Kind       string `json:"kind"       default:"deployment"`
+	Name       string `json:"name"`
+	// MinReady 为 0 时，workload 使用期望副本数，service 至少需要 1 个 ready endpoint
+	MinReady int `json:"minReady"`
+	Timeout  int `json:"timeout"    default:"10"`
+}
+
+func (c *Checker) Validate(fields string) error {
+	_, err := c.fromFields(fields)
+	return err
+}
+
+func (c *Checker) fromFields(fieldsStr string) (*fields, error) {
+	f := new(fields)
+	if err := json.Unmarshal([]byte(fieldsStr), &f); err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+	defaults.SetDefaults(f)
+
+	if !slices.Contains([]string{KindDeployment, KindStatefulSet, KindService}, f.Kind) {
+		return nil, fmt.Errorf("kind must be one of %s, %s, %s", KindDeployment, KindStatefulSet, KindService)
+	}
+	if f.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if f.MinReady < 0 {
+		return nil, errors.New("minReady must not be negative")
+	}
+	if f.Timeout <= 0 {
+		return nil, errors.New("timeout must be greater than 0")
+	}
+	if f.Kubeconfig != "" {
+		path, err := kubeconfigPath(f.Kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		f.Kubeconfig = path
+	}
+
+	return f, nil
+}
+
+// kubeconfigPath 将服务填写的文件名解析为 kubeconfig 目录下的路径，不允许引用服务端的其他文件
+func kubeconfigPath(name string) (string, error) {
+	dir := ""
+	if cfg := config.Current(); cfg != nil {
+		dir = cfg.Kubernetes.KubeconfigDir
+	}
+	if dir == "" {
+		return "", errors.New("kubeconfig is not allowed because kubernetes.kubeconfigDir is not configured")
+	}
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return "", errors.New("kubeconfig must be a file name in the kubeconfig directory")
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func (c *Checker) Check(ctx context.Context, fieldStr string) *model.Record {
+	fs, err := c.fromFields(fieldStr)
+	if err != nil {
+		return &model.Record{
+			IsSuccess: false,
+			Message:   err.Error(),
+			MonitorAt: time.Now(),
+		}
+	}
+
+	start := time.Now()
+	record := &model.Record{
+		MonitorAt: start,
+	}
+
+	client, err := c.newClient(fs)
+	if err != nil {
+		record.IsSuccess = false
+		record.Message = err.Error()
+		return record
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(fs.Timeout)*time.Second)
+	defer cancel()
+
+	var ready, want int
+	switch fs.Kind {
+	case KindDeployment:
+		ready, want, err = c.deployment(ctx, client, fs)
+	case KindStatefulSet:
+		ready, want, err = c.statefulSet(ctx, client, fs)
+	case KindService:
+		ready, want, err = c.service(ctx, client, fs)
+	}
+	if err != nil {
+		record.IsSuccess = false
+		record.Message = err.Error()
+		return record
+	}
+	record.ResponseTime = time.Since(start).Milliseconds()
+
+	if fs.MinReady > 0 {
+		want = fs.MinReady
+	}
+	record.IsSuccess = ready >= want
+	if record.IsSuccess {
+		record.Message = fmt.Sprintf("OK, %d/%d ready", ready, want)
+	} else {
+		record.Message = fmt.Sprintf("%s %s/%s has %d ready, expected %d", fs.Kind, fs.Namespace, fs.Name, ready, want)
+	}
+
+	return record
+}
+
+func (c *Checker) newClient(fs *fields) (kubernetes.Interface, error) {
+	var (
+		cfg *rest.Config
+		err error
+	)
+	if fs.Kubeconfig == "" {
+		cfg, err = rest.InClusterConfig()
+	} else {
+		cfg, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: fs.Kubeconfig},
+			&clientcmd.ConfigOverrides{CurrentContext: fs.Context},
+		).ClientConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes config: %w", err)
+	}
+	cfg.Timeout = time.Duration(fs.Timeout) * time.Second
+
+	return kubernetes.NewForConfig(cfg)
+}
+
+func (c *Checker) deployment(ctx context.Context, client kubernetes.Interface, fs *fields) (int, int, error) {
+	d, err := client.AppsV1().Deployments(fs.Namespace).Get(ctx, fs.Name, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	want := 1
+	if d.Spec.Replicas != nil {
+		want = int(*d.Spec.Replicas)
+	}
+	return int(d.Status.ReadyReplicas), want, nil
+}
+
+func (c *Checker) statefulSet(ctx context.Context, client kubernetes.Interface, fs *fields) (int, int, error) {
+	s, err := client.AppsV1().StatefulSets(fs.Namespace).Get(ctx, fs.Name, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	want := 1
+	if s.Spec.Replicas != nil {
+		want = int(*s.Spec.Replicas)
+	}
+	return int(s.Status.ReadyReplicas), want, nil
+}
+
+func (c *Checker) service(ctx context.Context, client kubernetes.Interface, fs *fields) (int, int, error) {
+	if _, err := client.CoreV1().Services(fs.Namespace).Get(ctx, fs.Name, metav1.GetOptions{}); err != nil {
+		return 0, 0, err
+	}
+
+	list, err := client.DiscoveryV1().EndpointSlices(fs.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, fs.Name),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// 双栈 service 的同一个 pod 会同时出现在 IPv4 和 IPv6 的 slice 中，按 targetRef 去重
+	ready := make(map[string]struct{})
+	for _, slice := range list.Items {
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			switch {
+			case ep.TargetRef != nil:
+				ready[ep.TargetRef.Namespace+"/"+ep.TargetRef.Name] = struct{}{}
+			case len(ep.Addresses) > 0:
+				ready[ep.Addresses[0]] = struct{}{}
+			}
+		}
+	}
+	return len(ready), 1, nil
+}
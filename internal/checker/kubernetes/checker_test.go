@@ -0,0 +1,131 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/toodofun/pulse/internal/config"
+)
+
+const kubeconfigTemplate = `apiVersion: v1
+kind: Config
+clusters:
+- name: fake
+  cluster:
+    server: %s
+contexts:
+- name: fake
+  context:
+    cluster: fake
+    user: fake
+current-context: fake
+users:
+- name: fake
+  user:
+    token: fake
+`
+
+func TestChecker_Check(t *testing.T) {
+	objects := map[string]string{
+		"/apis/apps/v1/namespaces/default/deployments/web": `{"kind":"Deployment","apiVersion":"apps/v1",` +
+			`"metadata":{"name":"web"},"spec":{"replicas":3},"status":{"readyReplicas":3}}`,
+		"/apis/apps/v1/namespaces/default/deployments/degraded": `{"kind":"Deployment","apiVersion":"apps/v1",` +
+			`"metadata":{"name":"degraded"},"spec":{"replicas":3},"status":{"readyReplicas":1}}`,
+		"/apis/apps/v1/namespaces/db/statefulsets/pg": `{"kind":"StatefulSet","apiVersion":"apps/v1",` +
+			`"metadata":{"name":"pg"},"spec":{"replicas":2},"status":{"readyReplicas":2}}`,
+		"/api/v1/namespaces/default/services/api": `{"kind":"Service","apiVersion":"v1","metadata":{"name":"api"}}`,
+		"/apis/discovery.k8s.io/v1/namespaces/default/endpointslices": `{"kind":"EndpointSliceList","apiVersion":"discovery.k8s.io/v1",` +
+			`"items":[{"metadata":{"name":"api-x"},"addressType":"IPv4","endpoints":[` +
+			`{"addresses":["10.0.0.1"],"conditions":{"ready":true},"targetRef":{"kind":"Pod","namespace":"default","name":"api-1"}},` +
+			`{"addresses":["10.0.0.2"],"conditions":{"ready":false},"targetRef":{"kind":"Pod","namespace":"default","name":"api-2"}}]}]}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := objects[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	config.New("").Kubernetes.KubeconfigDir = dir
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(fmt.Sprintf(kubeconfigTemplate, server.URL)), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		fields string
+		want   bool
+	}{
+		{name: "deployment ready", fields: `"kind":"deployment","name":"web"`, want: true},
+		{name: "deployment degraded", fields: `"kind":"deployment","name":"degraded"`, want: false},
+		{name: "deployment min ready", fields: `"kind":"deployment","name":"degraded","minReady":1`, want: true},
+		{name: "statefulset ready", fields: `"kind":"statefulset","namespace":"db","name":"pg"`, want: true},
+		{name: "service endpoints", fields: `"kind":"service","name":"api"`, want: true},
+		{name: "service not enough endpoints", fields: `"kind":"service","name":"api","minReady":2`, want: false},
+		{name: "missing", fields: `"kind":"deployment","name":"missing"`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Checker{}
+			got := c.Check(context.Background(), fmt.Sprintf(`{"kubeconfig":"config",%s}`, tt.fields))
+			if got.IsSuccess != tt.want {
+				t.Errorf("Check() IsSuccess = %v, want %v, message %q", got.IsSuccess, tt.want, got.Message)
+			}
+		})
+	}
+}
+
+func TestChecker_Validate(t *testing.T) {
+	cfg := config.New("")
+
+	tests := []struct {
+		name    string
+		dir     string
+		fields  string
+		wantErr bool
+	}{
+		{name: "in-cluster", fields: `{"name":"web"}`},
+		{name: "file in kubeconfig dir", dir: "/etc/pulse/kube", fields: `{"kubeconfig":"prod","name":"web"}`},
+		{name: "kubeconfig dir not configured", fields: `{"kubeconfig":"prod","name":"web"}`, wantErr: true},
+		{name: "absolute path", dir: "/etc/pulse/kube", fields: `{"kubeconfig":"/etc/passwd","name":"web"}`, wantErr: true},
+		{name: "path traversal", dir: "/etc/pulse/kube", fields: `{"kubeconfig":"../../passwd","name":"web"}`, wantErr: true},
+		{name: "missing name", fields: `{"kind":"service"}`, wantErr: true},
+		{name: "unknown kind", fields: `{"kind":"pod","name":"web"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Kubernetes.KubeconfigDir = tt.dir
+			c := &Checker{}
+			if err := c.Validate(tt.fields); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/mcuadros/go-defaults"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const (
+	CheckerTypeLDAP model.CheckerType = "ldap"
+)
+
+var scopes = map[string]int{
+	"base": ldap.ScopeBaseObject,
+	"one":  ldap.ScopeSingleLevel,
+	"sub":  ldap.ScopeWholeSubtree,
+}
+
+type Checker struct {
+}
+
+type fields struct {
+	URL                string `json:"url"`
+	Timeout            int    `json:"timeout"            default:"10"`
+	StartTLS           bool   `json:"startTLS"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	BindDN             string `json:"bindDN"`
+	BindPassword       string `json:"bindPassword"`
+	BaseDN             string `json:"baseDN"`
+	Filter             string `json:"filter"             default:"(objectClass=*)"`
+	Scope              string `json:"scope"              default:"sub"`
+	// 搜索至少返回的条目数，未配置时为 1，为 0 时只要求搜索成功
+	MinEntries *int `json:"minEntries"`
+}
+
+func (c *Checker) Validate(fields string) error {
+	_, err := c.fromFields(fields)
+	return err
+}
+
+func (c *Checker) fromFields(fieldsStr string) (*fields, error) {
+	f := new(fields)
+	if err := json.Unmarshal([]byte(fieldsStr), &f); err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+	defaults.SetDefaults(f)
+
+	u, err := url.Parse(f.URL)
+	if err != nil || u.Host == "" {
+		return nil, errors.New("url is required and must look like ldap://host:389 or ldaps://host:636")
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, errors.New("url scheme must be ldap or ldaps")
+	}
+	if u.Scheme == "ldaps" && f.StartTLS {
+		return nil, errors.New("startTLS cannot be used with ldaps")
+	}
+	if f.BindDN == "" && f.BindPassword != "" {
+		return nil, errors.New("bindDN is required when bindPassword is set")
+	}
+	if _, ok := scopes[f.Scope]; !ok {
+		return nil, fmt.Errorf("scope must be one of base, one, sub")
+	}
+	if f.MinEntries == nil {
+		minEntries := 1
+		f.MinEntries = &minEntries
+	}
+	if *f.MinEntries < 0 {
+		return nil, errors.New("minEntries must not be negative")
+	}
+	if f.Timeout <= 0 {
+		return nil, errors.New("timeout must be greater than 0")
+	}
+
+	return f, nil
+}
+
+func (c *Checker) Check(ctx context.Context, fieldStr string) *model.Record {
+	fs, err := c.fromFields(fieldStr)
+	if err != nil {
+		return &model.Record{
+			IsSuccess: false,
+			Message:   err.Error(),
+			MonitorAt: time.Now(),
+		}
+	}
+
+	start := time.Now()
+	record := &model.Record{
+		MonitorAt: start,
+	}
+
+	if err = c.probe(ctx, fs); err != nil {
+		record.IsSuccess = false
+		record.Message = err.Error()
+		return record
+	}
+
+	record.IsSuccess = true
+	record.ResponseTime = time.Since(start).Milliseconds()
+	record.Message = "OK"
+	return record
+}
+
+func (c *Checker) probe(ctx context.Context, fs *fields) error {
+	timeout := time.Duration(fs.Timeout) * time.Second
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: fs.InsecureSkipVerify,
+	}
+	if u, err := url.Parse(fs.URL); err == nil {
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	conn, err := ldap.DialURL(
+		fs.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return fmt.Errorf("connect failed: %w", err)
+	}
+	defer conn.Close()
+	conn.SetTimeout(timeout)
+
+	// go-ldap 不支持 context，取消时关闭连接以中断阻塞中的请求
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	if fs.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("starttls failed: %w", err)
+		}
+	}
+
+	if fs.BindDN != "" {
+		if fs.BindPassword == "" {
+			err = conn.UnauthenticatedBind(fs.BindDN)
+		} else {
+			err = conn.Bind(fs.BindDN, fs.BindPassword)
+		}
+		if err != nil {
+			return fmt.Errorf("bind failed: %w", err)
+		}
+	}
+
+	if fs.BaseDN == "" {
+		return nil
+	}
+
+	res, err := conn.Search(ldap.NewSearchRequest(
+		fs.BaseDN,
+		scopes[fs.Scope],
+		ldap.NeverDerefAliases,
+		0,
+		fs.Timeout,
+		false,
+		fs.Filter,
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	if len(res.Entries) < *fs.MinEntries {
+		return fmt.Errorf("search returned %d entries, expected at least %d", len(res.Entries), *fs.MinEntries)
+	}
+
+	return nil
+}
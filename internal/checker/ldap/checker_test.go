@@ -0,0 +1,48 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import "testing"
+
+func TestChecker_fromFields(t *testing.T) {
+	tests := []struct {
+		name           string
+		fields         string
+		wantMinEntries int
+		wantErr        bool
+	}{
+		{name: "defaults", fields: `{"url":"ldap://ldap.example.com:389"}`, wantMinEntries: 1},
+		{name: "bind only", fields: `{"url":"ldaps://ldap.example.com","baseDN":"dc=example,dc=com","minEntries":0}`, wantMinEntries: 0},
+		{name: "min entries", fields: `{"url":"ldap://ldap.example.com","minEntries":3}`, wantMinEntries: 3},
+		{name: "negative min entries", fields: `{"url":"ldap://ldap.example.com","minEntries":-1}`, wantErr: true},
+		{name: "missing url", fields: `{}`, wantErr: true},
+		{name: "wrong scheme", fields: `{"url":"http://ldap.example.com"}`, wantErr: true},
+		{name: "starttls with ldaps", fields: `{"url":"ldaps://ldap.example.com","startTLS":true}`, wantErr: true},
+		{name: "password without bind dn", fields: `{"url":"ldap://ldap.example.com","bindPassword":"secret"}`, wantErr: true},
+		{name: "invalid scope", fields: `{"url":"ldap://ldap.example.com","scope":"tree"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Checker{}
+			got, err := c.fromFields(tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fromFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got.MinEntries != tt.wantMinEntries {
+				t.Errorf("fromFields() MinEntries = %d, want %d", *got.MinEntries, tt.wantMinEntries)
+			}
+		})
+	}
+}
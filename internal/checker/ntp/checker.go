@@ -0,0 +1,153 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ntp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/beevik/ntp"
+	"github.com/mcuadros/go-defaults"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const (
+	CheckerTypeNTP model.CheckerType = "ntp"
+)
+
+type Checker struct {
+}
+
+type fields struct {
+	// Server 可以是 host 或 host:port，默认端口 123
+	Server     string `json:"server"`
+	Timeout    int    `json:"timeout"    default:"5"`
+	Version    int    `json:"version"    default:"4"`
+	MaxOffset  int64  `json:"maxOffset"  default:"1000"` // 毫秒
+	MaxStratum int    `json:"maxStratum" default:"3"`
+}
+
+func (c *Checker) Validate(fields string) error {
+	_, err := c.fromFields(fields)
+	return err
+}
+
+func (c *Checker) fromFields(fieldsStr string) (*fields, error) {
+	f := new(fields)
+	if err := json.Unmarshal([]byte(fieldsStr), &f); err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+	defaults.SetDefaults(f)
+
+	if f.Server == "" {
+		return nil, errors.New("server is required")
+	}
+	if f.Version < 2 || f.Version > 4 {
+		return nil, errors.New("version must be between 2 and 4")
+	}
+	if f.MaxOffset <= 0 {
+		return nil, errors.New("maxOffset must be greater than 0")
+	}
+	if f.MaxStratum <= 0 || f.MaxStratum > 15 {
+		return nil, errors.New("maxStratum must be between 1 and 15")
+	}
+	if f.Timeout <= 0 {
+		return nil, errors.New("timeout must be greater than 0")
+	}
+
+	return f, nil
+}
+
+func (c *Checker) Check(ctx context.Context, fieldStr string) *model.Record {
+	fs, err := c.fromFields(fieldStr)
+	if err != nil {
+		return &model.Record{
+			IsSuccess: false,
+			Message:   err.Error(),
+			MonitorAt: time.Now(),
+		}
+	}
+
+	start := time.Now()
+	record := &model.Record{
+		MonitorAt: start,
+	}
+
+	resp, err := c.query(ctx, fs)
+	if err != nil {
+		record.IsSuccess = false
+		record.Message = err.Error()
+		return record
+	}
+	record.ResponseTime = resp.RTT.Milliseconds()
+
+	if err = resp.Validate(); err != nil {
+		record.IsSuccess = false
+		record.Message = fmt.Sprintf("invalid response: %v", err)
+		return record
+	}
+
+	offset := resp.ClockOffset
+	maxOffset := time.Duration(fs.MaxOffset) * time.Millisecond
+	switch {
+	case int(resp.Stratum) > fs.MaxStratum:
+		record.IsSuccess = false
+		record.Message = fmt.Sprintf("stratum %d is higher than %d", resp.Stratum, fs.MaxStratum)
+	case offset > maxOffset || offset < -maxOffset:
+		record.IsSuccess = false
+		record.Message = fmt.Sprintf("clock offset %s exceeds %s", offset, maxOffset)
+	default:
+		record.IsSuccess = true
+		record.Message = fmt.Sprintf("OK, offset %s, stratum %d", offset, resp.Stratum)
+	}
+
+	return record
+}
+
+func (c *Checker) query(ctx context.Context, fs *fields) (*ntp.Response, error) {
+	var (
+		mu   sync.Mutex
+		conn net.Conn
+	)
+
+	// ntp 库不支持 context，取消时关闭 UDP 连接以中断等待
+	stop := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if conn != nil {
+			conn.Close()
+		}
+	})
+	defer stop()
+
+	return ntp.QueryWithOptions(fs.Server, ntp.QueryOptions{
+		Timeout: time.Duration(fs.Timeout) * time.Second,
+		Version: fs.Version,
+		Dialer: func(_, remoteAddress string) (net.Conn, error) {
+			var d net.Dialer
+			udp, err := d.DialContext(ctx, "udp", remoteAddress)
+			mu.Lock()
+			defer mu.Unlock()
+			conn = udp
+			return udp, err
+		},
+	})
+}
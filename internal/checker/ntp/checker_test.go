@@ -0,0 +1,49 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ntp
+
+import "testing"
+
+func TestChecker_fromFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		fields      string
+		wantVersion int
+		wantErr     bool
+	}{
+		{name: "defaults", fields: `{"server":"pool.ntp.org"}`, wantVersion: 4},
+		{name: "with port", fields: `{"server":"10.0.0.1:1123","version":3,"maxOffset":50,"maxStratum":15}`, wantVersion: 3},
+		{name: "missing server", fields: `{}`, wantErr: true},
+		{name: "version too low", fields: `{"server":"pool.ntp.org","version":1}`, wantErr: true},
+		{name: "version too high", fields: `{"server":"pool.ntp.org","version":5}`, wantErr: true},
+		{name: "negative max offset", fields: `{"server":"pool.ntp.org","maxOffset":-1}`, wantErr: true},
+		{name: "negative max stratum", fields: `{"server":"pool.ntp.org","maxStratum":-1}`, wantErr: true},
+		{name: "max stratum too high", fields: `{"server":"pool.ntp.org","maxStratum":16}`, wantErr: true},
+		{name: "negative timeout", fields: `{"server":"pool.ntp.org","timeout":-1}`, wantErr: true},
+		{name: "invalid json", fields: `{"server":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Checker{}
+			got, err := c.fromFields(tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fromFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Version != tt.wantVersion {
+				t.Errorf("fromFields() Version = %d, want %d", got.Version, tt.wantVersion)
+			}
+		})
+	}
+}
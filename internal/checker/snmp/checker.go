@@ -0,0 +1,341 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snmp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/mcuadros/go-defaults"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const (
+	CheckerTypeSNMP model.CheckerType = "snmp"
+
+	Version2c = "2c"
+	Version3  = "3"
+)
+
+var (
+	securityLevels = map[string]gosnmp.SnmpV3MsgFlags{
+		"noAuthNoPriv": gosnmp.NoAuthNoPriv,
+		"authNoPriv":   gosnmp.AuthNoPriv,
+		"authPriv":     gosnmp.AuthPriv,
+	}
+	authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+		"MD5":    gosnmp.MD5,
+		"SHA":    gosnmp.SHA,
+		"SHA224": gosnmp.SHA224,
+		"SHA256": gosnmp.SHA256,
+		"SHA384": gosnmp.SHA384,
+		"SHA512": gosnmp.SHA512,
+	}
+	privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+		"DES":     gosnmp.DES,
+		"AES":     gosnmp.AES,
+		"AES192":  gosnmp.AES192,
+		"AES256":  gosnmp.AES256,
+		"AES192C": gosnmp.AES192C,
+		"AES256C": gosnmp.AES256C,
+	}
+	operators = []string{"", "=", "!=", ">", ">=", "<", "<=", "contains"}
+)
+
+type Checker struct {
+}
+
+type fields struct {
+	Target  string `json:"target"`
+	Port    uint16 `json:"port"    default:"161"`
+	Version string `json:"version" default:"2c"`
+	Timeout int    `json:"timeout" default:"5"`
+	Retries int    `json:"retries" default:"1"`
+
+	// v2c
+	Community string `json:"community" default:"public"`
+
+	// v3
+	Username       string `json:"username"`
+	SecurityLevel  string `json:"securityLevel"  default:"authPriv"`
+	AuthProtocol   string `json:"authProtocol"   default:"SHA"`
+	AuthPassphrase string `json:"authPassphrase"`
+	PrivProtocol   string `json:"privProtocol"   default:"AES"`
+	PrivPassphrase string `json:"privPassphrase"`
+	ContextName    string `json:"contextName"`
+
+	Checks []assertion `json:"checks"`
+}
+
+// assertion 描述对单个 OID 取值的断言，Operator 为空时只要求 OID 存在
+type assertion struct {
+	OID      string `json:"oid"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+func (c *Checker) Validate(fields string) error {
+	_, err := c.fromFields(fields)
+	return err
+}
+
+func (c *Checker) fromFields(fieldsStr string) (*fields, error) {
+	f := new(fields)
+	if err := json.Unmarshal([]byte(fieldsStr), &f); err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+	defaults.SetDefaults(f)
+
+	if f.Target == "" {
+		return nil, errors.New("target is required")
+	}
+
+	switch f.Version {
+	case Version2c:
+		if f.Community == "" {
+			return nil, errors.New("community is required for snmp v2c")
+		}
+	case Version3:
+		if f.Username == "" {
+			return nil, errors.New("username is required for snmp v3")
+		}
+		level, ok := securityLevels[f.SecurityLevel]
+		if !ok {
+			return nil, errors.New("securityLevel must be one of noAuthNoPriv, authNoPriv, authPriv")
+		}
+		if level&gosnmp.AuthNoPriv != 0 {
+			if _, ok := authProtocols[f.AuthProtocol]; !ok {
+				return nil, fmt.Errorf("unsupported authProtocol: %s", f.AuthProtocol)
+			}
+			if f.AuthPassphrase == "" {
+				return nil, errors.New("authPassphrase is required")
+			}
+		}
+		if level == gosnmp.AuthPriv {
+			if _, ok := privProtocols[f.PrivProtocol]; !ok {
+				return nil, fmt.Errorf("unsupported privProtocol: %s", f.PrivProtocol)
+			}
+			if f.PrivPassphrase == "" {
+				return nil, errors.New("privPassphrase is required")
+			}
+		}
+	default:
+		return nil, errors.New("version must be 2c or 3")
+	}
+
+	if len(f.Checks) == 0 {
+		return nil, errors.New("at least one oid check is required")
+	}
+	for i, a := range f.Checks {
+		if a.OID == "" {
+			return nil, fmt.Errorf("checks[%d]: oid is required", i)
+		}
+		if !strings.HasPrefix(a.OID, ".") {
+			f.Checks[i].OID = "." + a.OID
+		}
+		if !slices.Contains(operators, a.Operator) {
+			return nil, fmt.Errorf("checks[%d]: operator must be one of %s", i, strings.Join(operators[1:], ", "))
+		}
+	}
+
+	if f.Timeout <= 0 {
+		return nil, errors.New("timeout must be greater than 0")
+	}
+
+	return f, nil
+}
+
+func (c *Checker) Check(ctx context.Context, fieldStr string) *model.Record {
+	fs, err := c.fromFields(fieldStr)
+	if err != nil {
+		return &model.Record{
+			IsSuccess: false,
+			Message:   err.Error(),
+			MonitorAt: time.Now(),
+		}
+	}
+
+	start := time.Now()
+	record := &model.Record{
+		MonitorAt: start,
+	}
+
+	values, err := c.get(ctx, fs)
+	if err != nil {
+		record.IsSuccess = false
+		record.Message = err.Error()
+		return record
+	}
+	record.ResponseTime = time.Since(start).Milliseconds()
+
+	results := make([]string, 0, len(fs.Checks))
+	for _, a := range fs.Checks {
+		pdu, ok := values[a.OID]
+		if !ok {
+			record.IsSuccess = false
+			record.Message = fmt.Sprintf("oid %s not returned", a.OID)
+			return record
+		}
+		if err = a.match(pdu); err != nil {
+			record.IsSuccess = false
+			record.Message = err.Error()
+			return record
+		}
+		results = append(results, fmt.Sprintf("%s=%s", a.OID, valueString(pdu)))
+	}
+
+	record.IsSuccess = true
+	record.Message = "OK, " + strings.Join(results, ", ")
+	return record
+}
+
+func (c *Checker) get(ctx context.Context, fs *fields) (map[string]gosnmp.SnmpPDU, error) {
+	client := &gosnmp.GoSNMP{
+		Target:             fs.Target,
+		Port:               fs.Port,
+		Transport:          "udp",
+		Context:            ctx,
+		Community:          fs.Community,
+		Version:            gosnmp.Version2c,
+		Timeout:            time.Duration(fs.Timeout) * time.Second,
+		Retries:            fs.Retries,
+		ExponentialTimeout: false,
+		MaxOids:            gosnmp.MaxOids,
+	}
+
+	if fs.Version == Version3 {
+		level := securityLevels[fs.SecurityLevel]
+		params := &gosnmp.UsmSecurityParameters{
+			UserName:               fs.Username,
+			AuthenticationProtocol: gosnmp.NoAuth,
+			PrivacyProtocol:        gosnmp.NoPriv,
+		}
+		if level&gosnmp.AuthNoPriv != 0 {
+			params.AuthenticationProtocol = authProtocols[fs.AuthProtocol]
+			params.AuthenticationPassphrase = fs.AuthPassphrase
+		}
+		if level == gosnmp.AuthPriv {
+			params.PrivacyProtocol = privProtocols[fs.PrivProtocol]
+			params.PrivacyPassphrase = fs.PrivPassphrase
+		}
+		client.Version = gosnmp.Version3
+		client.SecurityModel = gosnmp.UserSecurityModel
+		client.MsgFlags = level
+		client.SecurityParameters = params
+		client.ContextName = fs.ContextName
+	}
+
+	if err := client.Connect(); err != nil {
+		return nil, fmt.Errorf("connect failed: %w", err)
+	}
+	defer client.Conn.Close()
+
+	oids := make([]string, 0, len(fs.Checks))
+	for _, a := range fs.Checks {
+		oids = append(oids, a.OID)
+	}
+
+	res, err := client.Get(oids)
+	if err != nil {
+		return nil, fmt.Errorf("get failed: %w", err)
+	}
+	if res.Error != gosnmp.NoError {
+		return nil, fmt.Errorf("get failed: %s", res.Error)
+	}
+
+	values := make(map[string]gosnmp.SnmpPDU, len(res.Variables))
+	for _, v := range res.Variables {
+		switch v.Type {
+		case gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+			continue
+		}
+		values[v.Name] = v
+	}
+
+	return values, nil
+}
+
+func (a *assertion) match(pdu gosnmp.SnmpPDU) error {
+	if a.Operator == "" {
+		return nil
+	}
+
+	actual := valueString(pdu)
+	if a.Operator == "contains" {
+		if !strings.Contains(actual, a.Value) {
+			return fmt.Errorf("oid %s value %q does not contain %q", a.OID, actual, a.Value)
+		}
+		return nil
+	}
+
+	var cmp int
+	expected, ok := new(big.Int).SetString(a.Value, 10)
+	if isNumeric(pdu.Type) && ok {
+		cmp = gosnmp.ToBigInt(pdu.Value).Cmp(expected)
+	} else {
+		cmp = strings.Compare(actual, a.Value)
+	}
+
+	var matched bool
+	switch a.Operator {
+	case "=":
+		matched = cmp == 0
+	case "!=":
+		matched = cmp != 0
+	case ">":
+		matched = cmp > 0
+	case ">=":
+		matched = cmp >= 0
+	case "<":
+		matched = cmp < 0
+	case "<=":
+		matched = cmp <= 0
+	}
+	if !matched {
+		return fmt.Errorf("oid %s value %s is not %s %s", a.OID, actual, a.Operator, a.Value)
+	}
+	return nil
+}
+
+func isNumeric(t gosnmp.Asn1BER) bool {
+	switch t {
+	case gosnmp.Integer, gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Counter64, gosnmp.Uinteger32:
+		return true
+	default:
+		return false
+	}
+}
+
+func valueString(pdu gosnmp.SnmpPDU) string {
+	switch v := pdu.Value.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		if isNumeric(pdu.Type) {
+			return gosnmp.ToBigInt(v).String()
+		}
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snmp
+
+import (
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+func TestChecker_fromFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  string
+		wantOID string
+		wantErr bool
+	}{
+		{name: "v2c defaults", fields: `{"target":"10.0.0.1","checks":[{"oid":"1.3.6.1.2.1.1.3.0"}]}`, wantOID: ".1.3.6.1.2.1.1.3.0"},
+		{name: "leading dot kept", fields: `{"target":"10.0.0.1","checks":[{"oid":".1.3.6.1.2.1.1.3.0","operator":">","value":"0"}]}`, wantOID: ".1.3.6.1.2.1.1.3.0"},
+		{name: "v3 auth priv", fields: `{"target":"10.0.0.1","version":"3","username":"pulse","authPassphrase":"a","privPassphrase":"p","checks":[{"oid":"1.3"}]}`, wantOID: ".1.3"},
+		{name: "v3 no auth", fields: `{"target":"10.0.0.1","version":"3","username":"pulse","securityLevel":"noAuthNoPriv","checks":[{"oid":"1.3"}]}`, wantOID: ".1.3"},
+		{name: "missing target", fields: `{"checks":[{"oid":"1.3"}]}`, wantErr: true},
+		{name: "unknown version", fields: `{"target":"10.0.0.1","version":"1","checks":[{"oid":"1.3"}]}`, wantErr: true},
+		{name: "v3 without username", fields: `{"target":"10.0.0.1","version":"3","securityLevel":"noAuthNoPriv","checks":[{"oid":"1.3"}]}`, wantErr: true},
+		{name: "v3 invalid security level", fields: `{"target":"10.0.0.1","version":"3","username":"pulse","securityLevel":"all","checks":[{"oid":"1.3"}]}`, wantErr: true},
+		{name: "v3 unsupported auth protocol", fields: `{"target":"10.0.0.1","version":"3","username":"pulse","securityLevel":"authNoPriv","authProtocol":"SHA1","authPassphrase":"a","checks":[{"oid":"1.3"}]}`, wantErr: true},
+		{name: "v3 missing priv passphrase", fields: `{"target":"10.0.0.1","version":"3","username":"pulse","authPassphrase":"a","checks":[{"oid":"1.3"}]}`, wantErr: true},
+		{name: "no checks", fields: `{"target":"10.0.0.1"}`, wantErr: true},
+		{name: "missing oid", fields: `{"target":"10.0.0.1","checks":[{"operator":"="}]}`, wantErr: true},
+		{name: "unknown operator", fields: `{"target":"10.0.0.1","checks":[{"oid":"1.3","operator":"~"}]}`, wantErr: true},
+		{name: "negative timeout", fields: `{"target":"10.0.0.1","timeout":-1,"checks":[{"oid":"1.3"}]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Checker{}
+			got, err := c.fromFields(tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fromFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Checks[0].OID != tt.wantOID {
+				t.Errorf("fromFields() OID = %s, want %s", got.Checks[0].OID, tt.wantOID)
+			}
+		})
+	}
+}
+
+func TestAssertion_match(t *testing.T) {
+	integer := gosnmp.SnmpPDU{Type: gosnmp.Integer, Value: 10}
+	counter := gosnmp.SnmpPDU{Type: gosnmp.Counter64, Value: uint64(18446744073709551615)}
+	text := gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte("Linux router 5.10")}
+	tests := []struct {
+		name      string
+		assertion assertion
+		pdu       gosnmp.SnmpPDU
+		wantErr   bool
+	}{
+		{name: "exists", assertion: assertion{}, pdu: text},
+		{name: "number equal", assertion: assertion{Operator: "=", Value: "10"}, pdu: integer},
+		{name: "number compared numerically", assertion: assertion{Operator: ">", Value: "9"}, pdu: integer},
+		{name: "number less or equal", assertion: assertion{Operator: "<=", Value: "9"}, pdu: integer, wantErr: true},
+		{name: "large counter", assertion: assertion{Operator: ">=", Value: "18446744073709551615"}, pdu: counter},
+		{name: "number against text", assertion: assertion{Operator: "!=", Value: "ten"}, pdu: integer},
+		{name: "text equal", assertion: assertion{Operator: "=", Value: "Linux router 5.10"}, pdu: text},
+		{name: "text not equal", assertion: assertion{Operator: "!=", Value: "Linux router 5.10"}, pdu: text, wantErr: true},
+		{name: "contains", assertion: assertion{Operator: "contains", Value: "router"}, pdu: text},
+		{name: "does not contain", assertion: assertion{Operator: "contains", Value: "switch"}, pdu: text, wantErr: true},
+		{name: "contains number", assertion: assertion{Operator: "contains", Value: "1"}, pdu: integer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertion.OID = ".1.3"
+			if err := tt.assertion.match(tt.pdu); (err != nil) != tt.wantErr {
+				t.Errorf("match() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Elector 周期性地获取或续约 Lock，在成为或不再是 leader 时回调
+type Elector struct {
+	lock    Lock
+	ttl     time.Duration
+	renew   time.Duration
+	leading atomic.Bool
+
+	OnStartedLeading func()
+	OnStoppedLeading func()
+}
+
+func NewElector(lock Lock, ttl, renew time.Duration) *Elector {
+	return &Elector{
+		lock:  lock,
+		ttl:   ttl,
+		renew: renew,
+	}
+}
+
+func (e *Elector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// Run 阻塞运行直到 ctx 被取消，退出时释放锁
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.renew)
+	defer ticker.Stop()
+
+	var lastRenew time.Time
+	for {
+		held, err := e.lock.TryAcquire(ctx)
+		switch {
+		case err != nil:
+			logrus.Warnf("failed to acquire leader lock: %v", err)
+			// 续约失败但租约可能仍有效，只有超过租约时长才放弃 leader 身份
+			if e.IsLeader() && time.Since(lastRenew) >= e.ttl {
+				e.stepDown()
+			}
+		case held:
+			lastRenew = time.Now()
+			if e.leading.CompareAndSwap(false, true) {
+				logrus.Infof("became leader, start scheduling checks")
+				if e.OnStartedLeading != nil {
+					e.OnStartedLeading()
+				}
+			}
+		default:
+			e.stepDown()
+		}
+
+		select {
+		case <-ctx.Done():
+			e.stepDown()
+			releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := e.lock.Release(releaseCtx); err != nil {
+				logrus.Warnf("failed to release leader lock: %v", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Elector) stepDown() {
+	if e.leading.CompareAndSwap(true, false) {
+		logrus.Infof("lost leadership, stop scheduling checks")
+		if e.OnStoppedLeading != nil {
+			e.OnStoppedLeading()
+		}
+	}
+}
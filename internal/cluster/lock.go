@@ -0,0 +1,100 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/toodofun/pulse/internal/infra"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+// Lock 为可续约的分布式锁
+type Lock interface {
+	// TryAcquire 尝试获取或续约锁，返回当前实例是否持有该锁
+	TryAcquire(ctx context.Context) (bool, error)
+	// Release 主动释放锁，使其他实例无需等待租约过期即可接管
+	Release(ctx context.Context) error
+}
+
+// NewLock 根据数据库类型创建锁，SQLite 只支持单实例部署，使用空实现
+func NewLock(db *infra.Database, name, holder string, ttl time.Duration) (Lock, error) {
+	if db.Dialector.Name() == "sqlite" {
+		return &noopLock{}, nil
+	}
+
+	if err := db.AutoMigrate(&model.Lease{}); err != nil {
+		return nil, err
+	}
+	return &dbLock{
+		db:     db,
+		name:   name,
+		holder: holder,
+		ttl:    ttl,
+	}, nil
+}
+
+type noopLock struct {
+}
+
+func (l *noopLock) TryAcquire(_ context.Context) (bool, error) {
+	return true, nil
+}
+
+func (l *noopLock) Release(_ context.Context) error {
+	return nil
+}
+
+// dbLock 通过 leases 表中的一行实现租约：持有者续约，其他实例只能在租约过期后抢占
+type dbLock struct {
+	db     *infra.Database
+	name   string
+	holder string
+	ttl    time.Duration
+}
+
+func (l *dbLock) TryAcquire(ctx context.Context) (bool, error) {
+	now := time.Now()
+	db := l.db.WithContext(ctx)
+
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Lease{
+		Name:      l.name,
+		Holder:    l.holder,
+		ExpiresAt: now.Add(l.ttl),
+	}).Error; err != nil {
+		return false, err
+	}
+
+	res := db.Model(&model.Lease{}).
+		Where("name = ? AND (holder = ? OR expires_at < ?)", l.name, l.holder, now).
+		Updates(map[string]any{
+			"holder":     l.holder,
+			"expires_at": now.Add(l.ttl),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (l *dbLock) Release(ctx context.Context) error {
+	return l.db.WithContext(ctx).
+		Where("name = ? AND holder = ?", l.name, l.holder).
+		Delete(&model.Lease{}).Error
+}
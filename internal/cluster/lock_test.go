@@ -0,0 +1,75 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/toodofun/pulse/internal/config"
+	"github.com/toodofun/pulse/internal/infra"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+func TestDBLock(t *testing.T) {
+	db, err := infra.NewDatabase(config.Database{
+		Driver:      "sqlite",
+		DSN:         filepath.Join(t.TempDir(), "lock.sqlite"),
+		MaxOpenConn: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err = db.AutoMigrate(&model.Lease{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	ctx := context.Background()
+	ttl := 200 * time.Millisecond
+	a := &dbLock{db: db, name: "test", holder: "a", ttl: ttl}
+	b := &dbLock{db: db, name: "test", holder: "b", ttl: ttl}
+
+	steps := []struct {
+		name string
+		lock *dbLock
+		wait time.Duration
+		want bool
+	}{
+		{name: "a acquires", lock: a, want: true},
+		{name: "b blocked", lock: b, want: false},
+		{name: "a renews", lock: a, want: true},
+		{name: "b takes over after expiry", lock: b, wait: 2 * ttl, want: true},
+		{name: "a blocked", lock: a, want: false},
+	}
+	for _, step := range steps {
+		time.Sleep(step.wait)
+		got, err := step.lock.TryAcquire(ctx)
+		if err != nil {
+			t.Fatalf("%s: TryAcquire() error = %v", step.name, err)
+		}
+		if got != step.want {
+			t.Fatalf("%s: TryAcquire() = %v, want %v", step.name, got, step.want)
+		}
+	}
+
+	if err = b.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if got, _ := a.TryAcquire(ctx); !got {
+		t.Errorf("TryAcquire() after release = false, want true")
+	}
+}
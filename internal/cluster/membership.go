@@ -0,0 +1,82 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/toodofun/pulse/internal/infra"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+// Membership 通过 members 表维护存活的实例列表
+type Membership struct {
+	db  *infra.Database
+	id  string
+	ttl time.Duration
+}
+
+func NewMembership(db *infra.Database, id string, ttl time.Duration) (*Membership, error) {
+	if err := db.AutoMigrate(&model.Member{}); err != nil {
+		return nil, err
+	}
+	return &Membership{
+		db:  db,
+		id:  id,
+		ttl: ttl,
+	}, nil
+}
+
+func (m *Membership) ID() string {
+	return m.id
+}
+
+// Heartbeat 注册当前实例或刷新其心跳时间
+func (m *Membership) Heartbeat(ctx context.Context) error {
+	return m.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"heartbeat_at"}),
+	}).Create(&model.Member{
+		ID:          m.id,
+		HeartbeatAt: time.Now(),
+	}).Error
+}
+
+// Members 返回心跳未过期的实例，同时清理早已过期的记录
+func (m *Membership) Members(ctx context.Context) ([]string, error) {
+	now := time.Now()
+	db := m.db.WithContext(ctx)
+
+	if err := db.Where("heartbeat_at < ?", now.Add(-10*m.ttl)).Delete(&model.Member{}).Error; err != nil {
+		return nil, err
+	}
+
+	members := make([]string, 0)
+	if err := db.Model(&model.Member{}).
+		Where("heartbeat_at >= ?", now.Add(-m.ttl)).
+		Order("id").
+		Pluck("id", &members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// Leave 主动退出集群，使其他实例无需等待心跳过期即可接管
+func (m *Membership) Leave(ctx context.Context) error {
+	return m.db.WithContext(ctx).Delete(&model.Member{ID: m.id}).Error
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// 每个成员在哈希环上的虚拟节点数，使服务在成员间分布更均匀
+const virtualNodes = 128
+
+// Ring 为一致性哈希环，成员变化时只有少量服务需要迁移
+type Ring struct {
+	members []string
+	hashes  []uint64
+	owners  map[uint64]string
+}
+
+func NewRing(members []string) *Ring {
+	r := &Ring{
+		members: append([]string(nil), members...),
+		owners:  make(map[uint64]string, len(members)*virtualNodes),
+	}
+	sort.Strings(r.members)
+	for _, member := range r.members {
+		for i := 0; i < virtualNodes; i++ {
+			h := hashOf(member + "#" + strconv.Itoa(i))
+			if _, ok := r.owners[h]; ok {
+				continue
+			}
+			r.owners[h] = member
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+// Owner 返回负责 key 的成员，环为空时返回空字符串
+func (r *Ring) Owner(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	h := hashOf(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.owners[r.hashes[i]]
+}
+
+func (r *Ring) Members() []string {
+	return r.members
+}
+
+// Equal 判断两个环的成员是否相同
+func (r *Ring) Equal(other *Ring) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	if len(r.members) != len(other.members) {
+		return false
+	}
+	for i := range r.members {
+		if r.members[i] != other.members[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hashOf 在 fnv 的基础上再做一次混合，虚拟节点名只有末尾不同，直接使用 fnv 在环上分布不均
+func hashOf(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRing(t *testing.T) {
+	keys := make([]string, 3000)
+	for i := range keys {
+		keys[i] = uuid.NewMD5(uuid.NameSpaceOID, []byte(strconv.Itoa(i))).String()
+	}
+
+	three := NewRing([]string{"a", "b", "c"})
+	counts := make(map[string]int)
+	for _, key := range keys {
+		counts[three.Owner(key)]++
+	}
+	for _, member := range three.Members() {
+		// 期望每个成员约 1000 个，允许一定偏差
+		if counts[member] < 700 || counts[member] > 1300 {
+			t.Errorf("member %s owns %d keys, distribution is uneven: %v", member, counts[member], counts)
+		}
+	}
+
+	// 新成员加入时，只有迁移到新成员的服务改变归属
+	four := NewRing([]string{"d", "c", "b", "a"})
+	moved := 0
+	for _, key := range keys {
+		before, after := three.Owner(key), four.Owner(key)
+		if before != after {
+			moved++
+			if after != "d" {
+				t.Fatalf("key %s moved from %s to %s, want d", key, before, after)
+			}
+		}
+	}
+	if moved == 0 || moved > len(keys)/2 {
+		t.Errorf("moved %d of %d keys after join", moved, len(keys))
+	}
+
+	if !four.Equal(NewRing([]string{"a", "b", "c", "d"})) {
+		t.Errorf("Equal() = false for rings with the same members")
+	}
+	if owner := NewRing(nil).Owner(keys[0]); owner != "" {
+		t.Errorf("Owner() on empty ring = %q, want empty", owner)
+	}
+}
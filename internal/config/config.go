@@ -24,6 +24,7 @@ const (
 	ContextKeyConfig     ContextKey = "config"
 	ContextKeyInstanceId ContextKey = "instanceId"
 	ContextKeyUser       ContextKey = "user"
+	ContextKeyLocation   ContextKey = "location"
 )
 
 var config *Config
@@ -31,10 +32,16 @@ var config *Config
 type ContextKey string
 
 type Config struct {
-	Server      Server                 `json:"server"   yaml:"server"`
-	JWT         JWT                    `json:"jwt"      yaml:"jwt"`
-	OAuthConfig map[string]OAuthConfig `json:"oauth"    yaml:"oauth"`
-	Database    Database               `json:"database" yaml:"database"`
+	Server      Server                 `json:"server"    yaml:"server"`
+	JWT         JWT                    `json:"jwt"       yaml:"jwt"`
+	OAuthConfig map[string]OAuthConfig `json:"oauth"     yaml:"oauth"`
+	Database    Database               `json:"database"  yaml:"database"`
+	Scheduler   Scheduler              `json:"scheduler" yaml:"scheduler"`
+	Cluster     Cluster                `json:"cluster"   yaml:"cluster"`
+	Agent       Agent                  `json:"agent"     yaml:"agent"`
+	Flapping    Flapping               `json:"flapping"  yaml:"flapping"`
+	Notifier    Notifier               `json:"notifier"  yaml:"notifier"`
+	Kubernetes  Kubernetes             `json:"kubernetes" yaml:"kubernetes"`
 }
 
 func Current() *Config {
@@ -56,6 +63,8 @@ type Server struct {
 	Prefix      string        `json:"prefix"      yaml:"prefix"      default:"/api/v1"`
 	Debug       bool          `json:"debug"       yaml:"debug"       default:"false"`
 	GracePeriod time.Duration `json:"gracePeriod" yaml:"gracePeriod" default:"30s"`
+	// 允许接入的远程探测点及其 token，例如 {"eu-west": "secret"}
+	AgentTokens map[string]string `json:"agentTokens" yaml:"agentTokens"`
 }
 
 type JWT struct {
@@ -81,3 +90,66 @@ type Database struct {
 	ConnMaxLift time.Duration `json:"connMaxLift" yaml:"connMaxLift" default:"0s"`
 	ConnMaxIdle time.Duration `json:"connMaxIdle" yaml:"connMaxIdle" default:"0s"`
 }
+
+type Scheduler struct {
+	// 全局同时执行的检查数量
+	Workers int `json:"workers"    yaml:"workers"    default:"32"`
+	// 每种检查类型的队列长度，队列满时到期的检查会被丢弃
+	QueueSize int `json:"queueSize"  yaml:"queueSize"  default:"1024"`
+	// 按检查类型限制并发，例如 {"http": 16, "snmp": 4}
+	TypeLimits map[string]int `json:"typeLimits" yaml:"typeLimits"`
+	// cron 表达式调度的服务按 ID 延后 [0, CronJitter) 执行，间隔调度的服务则分散在整个周期内。
+	// 为负数时不延后，未配置或为 0 时使用默认值
+	CronJitter time.Duration `json:"cronJitter" yaml:"cronJitter" default:"10s"`
+}
+
+const (
+	ClusterModeLeader = "leader"
+	ClusterModeShard  = "shard"
+)
+
+type Cluster struct {
+	// leader: 只有 leader 调度所有检查；shard: 所有实例按一致性哈希分摊检查
+	Mode string `json:"mode"          yaml:"mode"          default:"leader"`
+	// leader 租约或成员心跳的有效时长，实例异常退出后其他实例最多等待该时长接管调度
+	LeaseDuration time.Duration `json:"leaseDuration" yaml:"leaseDuration" default:"15s"`
+	// 续约或心跳间隔，应明显小于 LeaseDuration
+	RenewInterval time.Duration `json:"renewInterval" yaml:"renewInterval" default:"5s"`
+	// 调度实例从数据库同步服务配置的间隔，用于感知其他实例通过 API 做出的修改
+	SyncInterval time.Duration `json:"syncInterval"  yaml:"syncInterval"  default:"10s"`
+}
+
+// Agent 为 agent 模式的配置，agent 从中心服务拉取分配给本探测点的服务并上报检查结果
+type Agent struct {
+	// 中心服务的 API 地址，例如 http://pulse.example.com/api/v1
+	Server   string `json:"server"   yaml:"server"`
+	Location string `json:"location" yaml:"location"`
+	Token    string `json:"token"    yaml:"token"`
+
+	SyncInterval time.Duration `json:"syncInterval" yaml:"syncInterval" default:"30s"`
+	PushInterval time.Duration `json:"pushInterval" yaml:"pushInterval" default:"5s"`
+	// 上报失败时缓存的检查结果数量，超出后丢弃最早的结果
+	BufferSize int `json:"bufferSize" yaml:"bufferSize" default:"10000"`
+}
+
+// Flapping 为抖动检测的配置，服务状态在 Window 内变化超过 Threshold 次视为抖动，
+// 抖动期间只发送一次抖动告警，直到 Window 内不再有状态变化
+type Flapping struct {
+	// 为负数时不检测抖动，未配置或为 0 时使用默认值
+	Threshold int           `json:"threshold" yaml:"threshold" default:"5"`
+	Window    time.Duration `json:"window"    yaml:"window"    default:"1h"`
+}
+
+// Notifier 为通知发送的配置，通知在后台由 Workers 个协程发送，队列满时新的通知被丢弃
+type Notifier struct {
+	Workers   int `json:"workers"   yaml:"workers"   default:"4"`
+	QueueSize int `json:"queueSize" yaml:"queueSize" default:"1024"`
+	// 单个渠道发送一次通知的超时时间，包含通知器自身的重试
+	Timeout time.Duration `json:"timeout" yaml:"timeout" default:"1m"`
+}
+
+// Kubernetes 为 kubernetes 检查的配置
+type Kubernetes struct {
+	// 服务可引用的 kubeconfig 文件所在的目录，服务只能填写该目录下的文件名；为空时只能使用 in-cluster 配置
+	KubeconfigDir string `json:"kubeconfigDir" yaml:"kubeconfigDir"`
+}
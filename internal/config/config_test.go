@@ -0,0 +1,55 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNew_Defaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaml       string
+		threshold  int
+		cronJitter time.Duration
+	}{
+		{name: "unset", yaml: "server:\n  port: 8080\n", threshold: 5, cronJitter: 10 * time.Second},
+		{name: "zero uses default", yaml: "flapping:\n  threshold: 0\nscheduler:\n  cronJitter: 0s\n",
+			threshold: 5, cronJitter: 10 * time.Second},
+		{name: "negative disables", yaml: "flapping:\n  threshold: -1\nscheduler:\n  cronJitter: -1s\n",
+			threshold: -1, cronJitter: -time.Second},
+		{name: "custom", yaml: "flapping:\n  threshold: 3\nscheduler:\n  cronJitter: 30s\n",
+			threshold: 3, cronJitter: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			cfg := New(path)
+			if cfg.Flapping.Threshold != tt.threshold {
+				t.Errorf("Flapping.Threshold = %d, want %d", cfg.Flapping.Threshold, tt.threshold)
+			}
+			if cfg.Scheduler.CronJitter != tt.cronJitter {
+				t.Errorf("Scheduler.CronJitter = %s, want %s", cfg.Scheduler.CronJitter, tt.cronJitter)
+			}
+		})
+	}
+}
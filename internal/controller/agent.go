@@ -0,0 +1,62 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/toodofun/pulse/internal/model"
+	"github.com/toodofun/pulse/internal/service"
+)
+
+// AgentController 提供给远程探测点的接口，使用探测点 token 鉴权
+type AgentController struct {
+	svc *service.MonitorService
+}
+
+func NewAgentController(svc *service.MonitorService) *AgentController {
+	return &AgentController{
+		svc: svc,
+	}
+}
+
+func (c *AgentController) handleListServices(ctx *gin.Context) {
+	services, err := c.svc.ListAgentServices(GetLocation(ctx))
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, services)
+}
+
+func (c *AgentController) handlePushRecords(ctx *gin.Context) {
+	var req []*model.Record
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		Reply(ctx, CodeParamError, nil)
+		return
+	}
+	saved, err := c.svc.SaveAgentRecords(GetLocation(ctx), req)
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, saved)
+}
+
+func (c *AgentController) RegisterRoute(group *gin.RouterGroup) {
+	api := group.Group("/agent")
+	api.GET("/services", c.handleListServices)
+	api.POST("/records", c.handlePushRecords)
+}
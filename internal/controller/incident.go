@@ -0,0 +1,160 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/toodofun/pulse/internal/service"
+)
+
+type IncidentController struct {
+	svc *service.IncidentService
+}
+
+func NewIncidentController(svc *service.IncidentService) *IncidentController {
+	return &IncidentController{
+		svc: svc,
+	}
+}
+
+// handleListIncidents 默认返回最近 30 天的故障，since/until 为 RFC3339 格式
+func (c *IncidentController) handleListIncidents(ctx *gin.Context) {
+	until := time.Now()
+	if v := ctx.Query("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			Reply(ctx, CodeParamError, nil)
+			return
+		}
+		until = t
+	}
+	since := until.AddDate(0, 0, -30)
+	if v := ctx.Query("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			Reply(ctx, CodeParamError, nil)
+			return
+		}
+		since = t
+	}
+
+	report, err := c.svc.ListIncidents(GetUser(ctx), ctx.Query("serviceId"), since, until)
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, report)
+}
+
+func (c *IncidentController) handleGetIncident(ctx *gin.Context) {
+	id, ok := incidentID(ctx)
+	if !ok {
+		return
+	}
+	incident, err := c.svc.GetIncident(id, GetUser(ctx))
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, incident)
+}
+
+func (c *IncidentController) handleAcknowledge(ctx *gin.Context) {
+	id, ok := incidentID(ctx)
+	if !ok {
+		return
+	}
+	if err := c.svc.Acknowledge(id, GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, nil)
+}
+
+func (c *IncidentController) handleAssign(ctx *gin.Context) {
+	id, ok := incidentID(ctx)
+	if !ok {
+		return
+	}
+	var req struct {
+		Assignee string `json:"assignee"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		Reply(ctx, CodeParamError, nil)
+		return
+	}
+	if err := c.svc.Assign(id, req.Assignee, GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, nil)
+}
+
+func (c *IncidentController) handleAddNote(ctx *gin.Context) {
+	id, ok := incidentID(ctx)
+	if !ok {
+		return
+	}
+	var req struct {
+		Message string `json:"message"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		Reply(ctx, CodeParamError, nil)
+		return
+	}
+	event, err := c.svc.AddNote(id, req.Message, GetUser(ctx))
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, event)
+}
+
+func (c *IncidentController) handleGetTimeline(ctx *gin.Context) {
+	id, ok := incidentID(ctx)
+	if !ok {
+		return
+	}
+	events, err := c.svc.GetTimeline(id, GetUser(ctx))
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, events)
+}
+
+func (c *IncidentController) RegisterRoute(group *gin.RouterGroup) {
+	api := group.Group("/incident")
+	api.GET("", c.handleListIncidents)
+	api.GET("/:id", c.handleGetIncident)
+	api.POST("/:id/ack", c.handleAcknowledge)
+	api.PUT("/:id/assignee", c.handleAssign)
+	api.POST("/:id/notes", c.handleAddNote)
+	api.GET("/:id/timeline", c.handleGetTimeline)
+}
+
+// incidentID 解析路径中的故障 ID，失败时直接回复参数错误
+func incidentID(ctx *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		Reply(ctx, CodeParamError, nil)
+		return 0, false
+	}
+	return id, true
+}
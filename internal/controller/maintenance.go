@@ -0,0 +1,83 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/toodofun/pulse/internal/model"
+	"github.com/toodofun/pulse/internal/service"
+)
+
+type MaintenanceController struct {
+	svc *service.MaintenanceService
+}
+
+func NewMaintenanceController(svc *service.MaintenanceService) *MaintenanceController {
+	return &MaintenanceController{
+		svc: svc,
+	}
+}
+
+func (c *MaintenanceController) handleListWindows(ctx *gin.Context) {
+	windows, err := c.svc.ListWindows(GetUser(ctx))
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, windows)
+}
+
+func (c *MaintenanceController) handleAddWindow(ctx *gin.Context) {
+	var req model.MaintenanceWindow
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		Reply(ctx, CodeParamError, nil)
+		return
+	}
+	if err := c.svc.AddWindow(&req, GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, req)
+}
+
+func (c *MaintenanceController) handleUpdateWindow(ctx *gin.Context) {
+	var req model.MaintenanceWindow
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		Reply(ctx, CodeParamError, nil)
+		return
+	}
+	if err := c.svc.UpdateWindow(&req, ctx.Param("id"), GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, nil)
+}
+
+func (c *MaintenanceController) handleDeleteWindow(ctx *gin.Context) {
+	if err := c.svc.DeleteWindow(ctx.Param("id"), GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, nil)
+}
+
+func (c *MaintenanceController) RegisterRoute(group *gin.RouterGroup) {
+	api := group.Group("/maintenance")
+	api.GET("", c.handleListWindows)
+	api.POST("", c.handleAddWindow)
+	api.PUT("/:id", c.handleUpdateWindow)
+	api.DELETE("/:id", c.handleDeleteWindow)
+}
@@ -134,17 +134,52 @@ func (c *MonitorController) handleUpdateService(ctx *gin.Context) {
 	}
 }
 
+func (c *MonitorController) handleGetSchedulerStats(ctx *gin.Context) {
+	Reply(ctx, CodeSuccess, c.svc.GetSchedulerStats())
+}
+
+func (c *MonitorController) handleGetDependencyGraph(ctx *gin.Context) {
+	graph, err := c.svc.GetDependencyGraph(GetUser(ctx))
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, graph)
+}
+
+func (c *MonitorController) handleCheckNow(ctx *gin.Context) {
+	record, err := c.svc.CheckNow(ctx.Request.Context(), ctx.Param("id"), GetUser(ctx))
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, record)
+}
+
+func (c *MonitorController) handleTestService(ctx *gin.Context) {
+	var req model.Service
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		Reply(ctx, CodeParamError, nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, c.svc.TestService(ctx.Request.Context(), &req, GetUser(ctx)))
+}
+
 func (c *MonitorController) RegisterRoute(group *gin.RouterGroup) {
 	api := group.Group("/monitor")
 	api.GET("/:id/daily", c.handleGetDailyRatio)
 	api.GET("/:id/daily/public", c.handleGetDailyRatioFromPublic)
 	api.GET("/:id/detail", c.handleGetService)
 	api.DELETE("/:id", c.handleDeleteService)
+	api.POST("/:id/check", c.handleCheckNow)
 	api.PUT("/:id", c.handleUpdateService)
 	api.PUT("/:id/enable", c.handleSetEnable)
 	api.PUT("/:id/disable", c.handleSetDisable)
 	api.PUT("/:id/private", c.handleSetPrivate)
 	api.PUT("/:id/public", c.handleSetPublic)
+	api.GET("/scheduler", c.handleGetSchedulerStats)
+	api.GET("/dependencies", c.handleGetDependencyGraph)
 	api.GET("", c.handleListServices)
 	api.POST("", c.handleAddService)
+	api.POST("/test", c.handleTestService)
 }
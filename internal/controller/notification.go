@@ -0,0 +1,135 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/toodofun/pulse/internal/model"
+	"github.com/toodofun/pulse/internal/service"
+)
+
+type NotificationController struct {
+	svc *service.NotificationService
+}
+
+func NewNotificationController(svc *service.NotificationService) *NotificationController {
+	return &NotificationController{
+		svc: svc,
+	}
+}
+
+func (c *NotificationController) handleListChannels(ctx *gin.Context) {
+	channels, err := c.svc.ListChannels(GetUser(ctx))
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, channels)
+}
+
+func (c *NotificationController) handleAddChannel(ctx *gin.Context) {
+	var req model.NotificationChannel
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		Reply(ctx, CodeParamError, nil)
+		return
+	}
+	if err := c.svc.AddChannel(&req, GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, req)
+}
+
+func (c *NotificationController) handleUpdateChannel(ctx *gin.Context) {
+	var req model.NotificationChannel
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		Reply(ctx, CodeParamError, nil)
+		return
+	}
+	if err := c.svc.UpdateChannel(&req, ctx.Param("id"), GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, nil)
+}
+
+func (c *NotificationController) handleSetEnable(ctx *gin.Context) {
+	if err := c.svc.SetChannelEnabled(ctx.Param("id"), true, GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, nil)
+}
+
+func (c *NotificationController) handleSetDisable(ctx *gin.Context) {
+	if err := c.svc.SetChannelEnabled(ctx.Param("id"), false, GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, nil)
+}
+
+func (c *NotificationController) handleDeleteChannel(ctx *gin.Context) {
+	if err := c.svc.DeleteChannel(ctx.Param("id"), GetUser(ctx)); err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, nil)
+}
+
+func (c *NotificationController) handleTestChannel(ctx *gin.Context) {
+	delivery, err := c.svc.TestChannel(ctx.Param("id"), GetUser(ctx))
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, delivery)
+}
+
+// handleListDeliveries 返回最近的发送记录，可按 channelId、serviceId 和 incidentId 过滤
+func (c *NotificationController) handleListDeliveries(ctx *gin.Context) {
+	var incidentID uint64
+	if v := ctx.Query("incidentId"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			Reply(ctx, CodeParamError, nil)
+			return
+		}
+		incidentID = id
+	}
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	deliveries, err := c.svc.ListDeliveries(GetUser(ctx), ctx.Query("channelId"), ctx.Query("serviceId"), incidentID, limit)
+	if err != nil {
+		Reply(ctx, NewCodeWithMsg(CodeUnknown, err.Error()), nil)
+		return
+	}
+	Reply(ctx, CodeSuccess, deliveries)
+}
+
+func (c *NotificationController) RegisterRoute(group *gin.RouterGroup) {
+	api := group.Group("/notification")
+	api.GET("/channel", c.handleListChannels)
+	api.POST("/channel", c.handleAddChannel)
+	api.PUT("/channel/:id", c.handleUpdateChannel)
+	api.PUT("/channel/:id/enable", c.handleSetEnable)
+	api.PUT("/channel/:id/disable", c.handleSetDisable)
+	api.DELETE("/channel/:id", c.handleDeleteChannel)
+	api.POST("/channel/:id/test", c.handleTestChannel)
+	api.GET("/delivery", c.handleListDeliveries)
+}
@@ -26,3 +26,10 @@ func GetUser(ctx *gin.Context) string {
 	}
 	return ""
 }
+
+func GetLocation(ctx *gin.Context) string {
+	if res, ok := ctx.Request.Context().Value(config.ContextKeyLocation).(string); ok {
+		return res
+	}
+	return ""
+}
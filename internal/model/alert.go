@@ -0,0 +1,37 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+const (
+	AlertIncidentOpened   = "incident.opened"
+	AlertIncidentResolved = "incident.resolved"
+	AlertFlapping         = "service.flapping"
+	AlertStable           = "service.stable"
+	// AlertTest 为测试通知渠道时发送的通知
+	AlertTest = "test"
+)
+
+// Alert 为需要通知的事件，Incident 为事件相关的故障，可能为空
+type Alert struct {
+	Type     string    `json:"type"`
+	Service  *Service  `json:"service"`
+	Incident *Incident `json:"incident,omitempty"`
+	Record   *Record   `json:"record,omitempty"`
+	At       time.Time `json:"at"`
+}
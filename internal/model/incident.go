@@ -0,0 +1,74 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+const (
+	IncidentOpen     = "open"
+	IncidentResolved = "resolved"
+)
+
+// Incident 为一次故障，服务确认 down 时打开，恢复 up 时解决
+type Incident struct {
+	ID         uint64     `json:"id"         gorm:"primary_key"`
+	ServiceID  string     `json:"serviceId"  gorm:"type:varchar(64);not null;index"`
+	Status     string     `json:"status"     gorm:"type:varchar(16);not null;index"`
+	StartedAt  time.Time  `json:"startedAt"  gorm:"not null;index"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+	// 持续时间（秒），未解决的故障为截至当前的时长
+	Duration int64 `json:"duration" gorm:"not null;default:0"`
+	// 本次故障中第一次失败的信息
+	Message   string   `json:"message"   gorm:"size:1024"`
+	Locations []string `json:"locations" gorm:"type:text;serializer:json"`
+
+	AcknowledgedAt *time.Time `json:"acknowledgedAt"`
+	AcknowledgedBy string     `json:"acknowledgedBy" gorm:"type:varchar(64)"`
+	Assignee       string     `json:"assignee"       gorm:"type:varchar(64);index"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+const (
+	IncidentEventOpened       = "opened"
+	IncidentEventResolved     = "resolved"
+	IncidentEventLocationDown = "location_down"
+	IncidentEventAcknowledged = "acknowledged"
+	IncidentEventAssigned     = "assigned"
+	IncidentEventNote         = "note"
+	IncidentEventFlapping     = "flapping"
+	IncidentEventStable       = "stable"
+	IncidentEventNotification = "notification"
+)
+
+// IncidentEvent 为故障时间线上的一条事件，CreatedBy 为空表示由系统产生
+type IncidentEvent struct {
+	ID         uint64    `json:"id"         gorm:"primary_key"`
+	IncidentID uint64    `json:"incidentId" gorm:"not null;index"`
+	Type       string    `json:"type"       gorm:"type:varchar(32);not null"`
+	Message    string    `json:"message"    gorm:"size:1024"`
+	CreatedBy  string    `json:"createdBy"  gorm:"type:varchar(64)"`
+	CreatedAt  time.Time `json:"createdAt"  gorm:"index"`
+}
+
+// IncidentReport 为一段时间内的故障统计
+type IncidentReport struct {
+	Count         int         `json:"count"`
+	TotalDuration int64       `json:"totalDuration"`
+	Incidents     []*Incident `json:"incidents"`
+}
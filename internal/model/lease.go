@@ -0,0 +1,27 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+// Lease 为基于数据库行的租约锁，Holder 在 ExpiresAt 之前持有该锁
+type Lease struct {
+	Name      string    `json:"name"      gorm:"type:varchar(64);primary_key"`
+	Holder    string    `json:"holder"    gorm:"type:varchar(128);not null"`
+	ExpiresAt time.Time `json:"expiresAt" gorm:"not null"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaintenanceWindow 为维护窗口，窗口内的检查照常执行，但失败会被标记为维护中，
+// 不计入可用率也不改变服务状态。StartAt/EndAt 为一次性维护，Windows 为周期性维护
+type MaintenanceWindow struct {
+	ID    string `json:"id"    gorm:"type:varchar(64);primary_key"`
+	Title string `json:"title" gorm:"type:varchar(255);not null"`
+
+	// 维护作用的服务，按服务 ID 或标签匹配
+	ServiceIDs []string `json:"serviceIds" gorm:"type:text;serializer:json"`
+	Tags       []string `json:"tags"       gorm:"type:text;serializer:json"`
+
+	StartAt  *time.Time     `json:"startAt"`
+	EndAt    *time.Time     `json:"endAt"`
+	Windows  []ActiveWindow `json:"windows"  gorm:"type:text;serializer:json"`
+	TimeZone string         `json:"timezone" gorm:"type:varchar(64)"`
+
+	CreatedBy string         `json:"createdBy" gorm:"type:varchar(64);not null;index"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+}
+
+func (m *MaintenanceWindow) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// Covers 判断维护窗口是否作用于该服务
+func (m *MaintenanceWindow) Covers(service *Service) bool {
+	if slices.Contains(m.ServiceIDs, service.ID) {
+		return true
+	}
+	for _, tag := range service.Tags {
+		if slices.Contains(m.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
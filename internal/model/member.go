@@ -0,0 +1,26 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+// Member 为分片模式下的集群成员，HeartbeatAt 超过租约时长未更新视为已离开
+type Member struct {
+	ID          string    `json:"id"          gorm:"type:varchar(128);primary_key"`
+	HeartbeatAt time.Time `json:"heartbeatAt" gorm:"not null;index"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
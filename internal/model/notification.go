@@ -0,0 +1,72 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotifierType string
+
+// NotificationChannel 为通知渠道，Config 为对应类型通知器的 JSON 配置，服务通过 Channels 绑定渠道
+type NotificationChannel struct {
+	ID      string       `json:"id"      gorm:"type:varchar(64);primary_key"`
+	Title   string       `json:"title"   gorm:"type:varchar(255);not null"`
+	Type    NotifierType `json:"type"    gorm:"type:varchar(16);not null"`
+	Enabled bool         `json:"enabled" gorm:"not null;default:true"`
+	Config  string       `json:"config"`
+	// 接收的通知类型，例如 incident.opened，为空表示接收所有类型
+	Alerts []string `json:"alerts" gorm:"type:text;serializer:json"`
+
+	CreatedBy string         `json:"createdBy" gorm:"type:varchar(64);not null;index"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+}
+
+func (m *NotificationChannel) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// Accepts 判断渠道是否接收该类型的通知
+func (m *NotificationChannel) Accepts(alertType string) bool {
+	return len(m.Alerts) == 0 || slices.Contains(m.Alerts, alertType)
+}
+
+const (
+	DeliverySuccess = "success"
+	DeliveryFailed  = "failed"
+)
+
+// NotificationDelivery 为一次通知的发送结果，IncidentID 为 0 表示通知与故障无关
+type NotificationDelivery struct {
+	ID         uint64 `json:"id"         gorm:"primary_key"`
+	ChannelID  string `json:"channelId"  gorm:"type:varchar(64);not null;index"`
+	ServiceID  string `json:"serviceId"  gorm:"type:varchar(64);index"`
+	IncidentID uint64 `json:"incidentId" gorm:"not null;default:0;index"`
+	AlertType  string `json:"alertType"  gorm:"type:varchar(32);not null"`
+	Status     string `json:"status"     gorm:"type:varchar(16);not null"`
+	Error      string `json:"error"      gorm:"type:text"`
+	// 发送耗时（毫秒）
+	Duration  int64     `json:"duration"  gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+}
@@ -20,12 +20,29 @@ import (
 	"gorm.io/gorm"
 )
 
+// SuppressReason 不为空表示该次失败已被抑制，不计入可用率也不改变服务状态
+const (
+	SuppressReasonMaintenance = "maintenance"
+	SuppressReasonDependency  = "dependency"
+)
+
 type Record struct {
-	ID           uint64         `json:"id"           gorm:"primary_key;"`
-	ServiceID    string         `json:"serviceId"    gorm:"type:varchar(64);not null;index"`
-	IsSuccess    bool           `json:"isSuccess"    gorm:"not null;index"`
-	ResponseTime int64          `json:"responseTime" gorm:"index"`
-	Message      string         `json:"message"      gorm:"size:1024"`
-	MonitorAt    time.Time      `json:"monitorAt"    gorm:"not null;index"`
-	DeletedAt    gorm.DeletedAt `json:"-"`
+	ID             uint64         `json:"id"                       gorm:"primary_key;"`
+	ServiceID      string         `json:"serviceId"                gorm:"type:varchar(64);not null;index"`
+	Location       string         `json:"location"                 gorm:"type:varchar(64);index"`
+	IsSuccess      bool           `json:"isSuccess"                gorm:"not null;index"`
+	ResponseTime   int64          `json:"responseTime"             gorm:"index"`
+	Message        string         `json:"message"                  gorm:"size:1024"`
+	MonitorAt      time.Time      `json:"monitorAt"                gorm:"not null;index"`
+	Attempts       []Attempt      `json:"attempts,omitempty"       gorm:"type:text;serializer:json"`
+	SuppressReason string         `json:"suppressReason,omitempty" gorm:"type:varchar(32);not null;default:''"`
+	DeletedAt      gorm.DeletedAt `json:"-"`
+}
+
+// Attempt 为一次检查中的单次尝试，仅在发生重试时记录
+type Attempt struct {
+	IsSuccess    bool      `json:"isSuccess"`
+	ResponseTime int64     `json:"responseTime"`
+	Message      string    `json:"message"`
+	MonitorAt    time.Time `json:"monitorAt"`
 }
@@ -26,11 +26,38 @@ type Service struct {
 	Title     string      `json:"title"     gorm:"type:varchar(255);not null"`
 	IsSuccess bool        `json:"isSuccess" gorm:"-"`
 	Interval  int         `json:"interval"  gorm:"not null;default:300"`
-	Type      CheckerType `json:"type"      gorm:"index;type:varchar(8);not null"`
+	Type      CheckerType `json:"type"      gorm:"index;type:varchar(16);not null"`
 	Enabled   bool        `json:"enabled"   gorm:"not null;default:true"`
 	Private   bool        `json:"private"   gorm:"not null;default:true"`
 	Fields    string      `json:"fields"`
 	Records   []Record    `json:"records"   gorm:"-"`
+	Tags      []string    `json:"tags"      gorm:"type:text;serializer:json"`
+
+	// 依赖的上游服务，任一上游 down 时本服务的失败记为 dependency
+	Parents []string `json:"parents" gorm:"type:text;serializer:json"`
+	// 最近一次失败被抑制的原因，为空表示未被抑制
+	SuppressReason string `json:"suppressReason,omitempty" gorm:"-"`
+	Flapping       bool   `json:"flapping"                 gorm:"-"`
+	// 故障打开、解决等事件发送到的通知渠道
+	Channels []string `json:"channels" gorm:"type:text;serializer:json"`
+
+	// 失败后在同一次检查内的重试次数及间隔（秒）
+	Retries       int `json:"retries"       gorm:"not null;default:0"`
+	RetryInterval int `json:"retryInterval" gorm:"not null;default:0"`
+	// 连续失败多少次后才确认服务为 down
+	FailureThreshold int `json:"failureThreshold" gorm:"not null;default:1"`
+
+	// Cron 不为空时替代 Interval 作为调度表达式，秒字段可选
+	Cron     string `json:"cron"     gorm:"type:varchar(128)"`
+	TimeZone string `json:"timezone" gorm:"type:varchar(64)"`
+	// 活跃时间窗口，为空表示全天检查，窗口外不执行检查
+	Windows []ActiveWindow `json:"windows" gorm:"type:text;serializer:json"`
+
+	// 执行检查的探测点，为空表示只由中心服务检查，LocationLocal 表示中心服务本身
+	Locations []string `json:"locations" gorm:"type:text;serializer:json"`
+	// 多少个探测点 down 时判定服务 down：any、majority 或 all
+	LocationPolicy string          `json:"locationPolicy"           gorm:"type:varchar(16);not null;default:majority"`
+	LocationStates []LocationState `json:"locationStates,omitempty" gorm:"-"`
 
 	CreatedBy string         `json:"createdBy" gorm:"type:varchar(64);not null"`
 	CreatedAt time.Time      `json:"createdAt"`
@@ -40,9 +67,47 @@ type Service struct {
 
 type CheckerType string
 
+const LocationLocal = "local"
+
+const (
+	LocationPolicyAny      = "any"
+	LocationPolicyMajority = "majority"
+	LocationPolicyAll      = "all"
+)
+
+// ActiveWindow 为每天的一个时间段，Start/End 格式为 HH:MM，End 小于 Start 表示跨午夜
+type ActiveWindow struct {
+	// 0 表示周日，为空表示每天
+	Weekdays []time.Weekday `json:"weekdays"`
+	Start    string         `json:"start"`
+	End      string         `json:"end"`
+}
+
 func (m *Service) BeforeCreate(tx *gorm.DB) error {
 	if m.ID == "" {
 		m.ID = uuid.NewString()
 	}
 	return nil
 }
+
+func (m *Service) HasLocation(location string) bool {
+	for _, l := range m.Locations {
+		if l == location {
+			return true
+		}
+	}
+	return false
+}
+
+// ProbeLocations 返回检查该服务的所有探测点
+func (m *Service) ProbeLocations() []string {
+	if len(m.Locations) == 0 {
+		return []string{LocationLocal}
+	}
+	return m.Locations
+}
+
+// CheckedLocally 判断服务是否需要由中心服务自己检查
+func (m *Service) CheckedLocally() bool {
+	return len(m.Locations) == 0 || m.HasLocation(LocationLocal)
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// ServiceState 记录服务经过确认后的整体状态，由各探测点的状态按 LocationPolicy 决定。
+// Status 为空表示尚未确认，Failures 为确认 down 的探测点数量
+type ServiceState struct {
+	ServiceID     string     `json:"serviceId"     gorm:"type:varchar(64);primary_key"`
+	Status        string     `json:"status"        gorm:"type:varchar(16)"`
+	Failures      int        `json:"failures"      gorm:"not null;default:0"`
+	ChangedAt     time.Time  `json:"changedAt"`
+	Flapping      bool       `json:"flapping"      gorm:"not null;default:false"`
+	FlappingSince *time.Time `json:"flappingSince"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// LocationState 记录服务在单个探测点上的状态，Failures 为该探测点的连续失败次数
+type LocationState struct {
+	ServiceID string    `json:"serviceId" gorm:"type:varchar(64);primary_key"`
+	Location  string    `json:"location"  gorm:"type:varchar(64);primary_key"`
+	Status    string    `json:"status"    gorm:"type:varchar(16)"`
+	Failures  int       `json:"failures"  gorm:"not null;default:0"`
+	ChangedAt time.Time `json:"changedAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
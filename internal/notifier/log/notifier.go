@@ -0,0 +1,81 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mcuadros/go-defaults"
+	"github.com/sirupsen/logrus"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const (
+	NotifierTypeLog model.NotifierType = "log"
+)
+
+// Notifier 将通知写入 Pulse 自身的日志，用于验证渠道绑定或由日志系统采集
+type Notifier struct {
+}
+
+type config struct {
+	Level string `json:"level" default:"warn"`
+}
+
+func (n *Notifier) Validate(config string) error {
+	_, err := n.fromConfig(config)
+	return err
+}
+
+func (n *Notifier) fromConfig(configStr string) (*config, error) {
+	c := new(config)
+	if configStr != "" {
+		if err := json.Unmarshal([]byte(configStr), &c); err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	defaults.SetDefaults(c)
+
+	if _, err := logrus.ParseLevel(c.Level); err != nil {
+		return nil, errors.New("level must be one of debug, info, warn and error")
+	}
+	return c, nil
+}
+
+func (n *Notifier) Notify(_ context.Context, configStr string, alert *model.Alert) error {
+	c, err := n.fromConfig(configStr)
+	if err != nil {
+		return err
+	}
+	level, _ := logrus.ParseLevel(c.Level)
+
+	entry := logrus.WithFields(logrus.Fields{
+		"alert":   alert.Type,
+		"service": alert.Service.Title,
+	})
+	if alert.Incident != nil {
+		entry = entry.WithField("incident", alert.Incident.ID)
+	}
+	message := ""
+	if alert.Record != nil {
+		message = alert.Record.Message
+	}
+	entry.Logf(level, "notification: %s", message)
+	return nil
+}
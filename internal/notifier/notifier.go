@@ -0,0 +1,41 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/toodofun/pulse/internal/model"
+	"github.com/toodofun/pulse/internal/notifier/log"
+	"github.com/toodofun/pulse/internal/notifier/webhook"
+)
+
+type Notifier interface {
+	// Notify 通过渠道发送一次通知，ctx 被取消时应尽快返回
+	Notify(ctx context.Context, config string, alert *model.Alert) error
+	Validate(config string) error
+}
+
+func GetNotifier(t model.NotifierType) (Notifier, error) {
+	switch t {
+	case log.NotifierTypeLog:
+		return &log.Notifier{}, nil
+	case webhook.NotifierTypeWebhook:
+		return &webhook.Notifier{}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier: %s", t)
+	}
+}
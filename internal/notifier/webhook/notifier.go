@@ -0,0 +1,210 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mcuadros/go-defaults"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const (
+	NotifierTypeWebhook model.NotifierType = "webhook"
+
+	HeaderEvent     = "X-Pulse-Event"
+	HeaderTimestamp = "X-Pulse-Timestamp"
+	// HeaderSignature 为 "sha256=" 加上以 Secret 对 "时间戳.请求体" 计算的 HMAC-SHA256 十六进制值
+	HeaderSignature = "X-Pulse-Signature"
+
+	maxBackoff = time.Minute
+)
+
+// Notifier 将通知以 HTTP 请求发送到任意地址，请求体由模板渲染，失败时按指数退避重试
+type Notifier struct {
+}
+
+type config struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"   default:"POST"`
+	Headers map[string]string `json:"headers"`
+	Secret  string            `json:"secret"`
+	// Go text/template 模板，数据为 model.Alert，为空时发送 Alert 的 JSON
+	Template string `json:"template"`
+	Timeout  int    `json:"timeout"  default:"10"` // 每次请求的超时（秒）
+	// 最多发送次数，1 表示不重试
+	Attempts int `json:"attempts" default:"4"`
+	// 首次重试前的等待（毫秒），之后每次翻倍
+	Backoff int `json:"backoff" default:"1000"`
+}
+
+var methods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodGet, http.MethodDelete}
+
+var funcs = template.FuncMap{
+	// json 将值编码为 JSON，用于在 JSON 模板中安全地嵌入字符串
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func (n *Notifier) Validate(config string) error {
+	_, _, err := n.fromConfig(config)
+	return err
+}
+
+func (n *Notifier) fromConfig(configStr string) (*config, *template.Template, error) {
+	c := new(config)
+	if err := json.Unmarshal([]byte(configStr), &c); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
+	}
+	defaults.SetDefaults(c)
+	c.Method = strings.ToUpper(c.Method)
+
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, nil, errors.New("url is required and must start with http:// or https://")
+	}
+	if !slices.Contains(methods, c.Method) {
+		return nil, nil, fmt.Errorf("unsupported method: %s", c.Method)
+	}
+	if c.Timeout <= 0 {
+		return nil, nil, errors.New("timeout must be greater than 0")
+	}
+	if c.Attempts <= 0 || c.Attempts > 10 {
+		return nil, nil, errors.New("attempts must be between 1 and 10")
+	}
+	if c.Backoff <= 0 {
+		return nil, nil, errors.New("backoff must be greater than 0")
+	}
+
+	var tmpl *template.Template
+	if c.Template != "" {
+		if tmpl, err = template.New("webhook").Funcs(funcs).Option("missingkey=error").Parse(c.Template); err != nil {
+			return nil, nil, fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	return c, tmpl, nil
+}
+
+func (n *Notifier) Notify(ctx context.Context, configStr string, alert *model.Alert) error {
+	c, tmpl, err := n.fromConfig(configStr)
+	if err != nil {
+		return err
+	}
+
+	body, err := render(tmpl, alert)
+	if err != nil {
+		return err
+	}
+
+	backoff := time.Duration(c.Backoff) * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		retryable, err := n.send(ctx, c, alert, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= c.Attempts {
+			return fmt.Errorf("attempt %d: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("attempt %d: %w", attempt, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// render 渲染请求体，服务的检查配置可能包含凭据，不会发送给接收方
+func render(tmpl *template.Template, alert *model.Alert) ([]byte, error) {
+	data := *alert
+	if alert.Service != nil {
+		service := *alert.Service
+		service.Fields = ""
+		data.Service = &service
+	}
+	if tmpl == nil {
+		return json.Marshal(&data)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, &data); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// send 发送一次请求，返回的 retryable 表示失败是否值得重试：网络错误、429 和 5xx 会重试，其余 4xx 不会
+func (n *Notifier) send(ctx context.Context, c *config, alert *model.Alert, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(c.Timeout)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, c.Method, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Pulse-Webhook")
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(HeaderEvent, alert.Type)
+	if c.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(HeaderTimestamp, timestamp)
+		req.Header.Set(HeaderSignature, "sha256="+Sign(c.Secret, timestamp, body))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	// 响应内容不放入错误中，错误会返回给测试渠道的用户，避免借此读取内网地址的响应
+	err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// Sign 计算请求签名，接收方应以同样方式计算并用常量时间比较
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+func TestNotifier_Notify(t *testing.T) {
+	alert := &model.Alert{
+		Type:     model.AlertIncidentOpened,
+		Service:  &model.Service{Title: "api", Fields: `{"password":"secret"}`},
+		Incident: &model.Incident{ID: 7, Status: model.IncidentOpen},
+		Record:   &model.Record{Message: `status "503"`},
+		At:       time.Now(),
+	}
+
+	tests := []struct {
+		name     string
+		config   string
+		statuses []int
+		want     string
+		attempts int32
+		wantErr  bool
+	}{
+		{
+			name:     "template",
+			config:   `"template":"{\"text\":{{printf \"%s is down: %s\" .Service.Title .Record.Message | json}},\"id\":{{.Incident.ID}}}"`,
+			statuses: []int{200},
+			want:     `{"text":"api is down: status \"503\"","id":7}`,
+			attempts: 1,
+		},
+		{
+			name:     "retry server error",
+			config:   `"template":"ok"`,
+			statuses: []int{500, 502, 204},
+			want:     "ok",
+			attempts: 3,
+		},
+		{
+			name:     "give up after attempts",
+			config:   `"template":"ok","attempts":2`,
+			statuses: []int{503, 503, 503},
+			attempts: 2,
+			wantErr:  true,
+		},
+		{
+			name:     "no retry on client error",
+			config:   `"template":"ok"`,
+			statuses: []int{400, 200},
+			attempts: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "template error",
+			config:   `"template":"{{.Missing}}"`,
+			attempts: 0,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var count atomic.Int32
+			var body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := count.Add(1)
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+				w.WriteHeader(tt.statuses[i-1])
+				_, _ = w.Write([]byte("internal response"))
+			}))
+			defer server.Close()
+
+			n := &Notifier{}
+			err := n.Notify(context.Background(), fmt.Sprintf(`{"url":%q,"backoff":1,%s}`, server.URL, tt.config), alert)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "internal response") {
+				t.Errorf("Notify() error should not contain the response body: %v", err)
+			}
+			if got := count.Load(); got != tt.attempts {
+				t.Errorf("Notify() attempts = %d, want %d", got, tt.attempts)
+			}
+			if !tt.wantErr && body != tt.want {
+				t.Errorf("Notify() body = %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifier_Signature(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	n := &Notifier{}
+	alert := &model.Alert{
+		Type:    model.AlertStable,
+		Service: &model.Service{Title: "api", Fields: `{"password":"secret"}`},
+	}
+	config := fmt.Sprintf(`{"url":%q,"method":"put","secret":"s3cr3t","headers":{"X-Team":"ops"}}`, server.URL)
+	if err := n.Notify(context.Background(), config, alert); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	want := "sha256=" + Sign("s3cr3t", header.Get(HeaderTimestamp), body)
+	if got := header.Get(HeaderSignature); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+	if got := header.Get(HeaderEvent); got != model.AlertStable {
+		t.Errorf("event = %s, want %s", got, model.AlertStable)
+	}
+	if got := header.Get("X-Team"); got != "ops" {
+		t.Errorf("custom header = %s, want ops", got)
+	}
+	if got := string(body); got == "" || strings.Contains(got, "secret") {
+		t.Errorf("default body should contain the alert without service fields, got %s", got)
+	}
+}
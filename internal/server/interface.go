@@ -15,6 +15,8 @@
 package server
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/toodofun/pulse/internal/infra"
@@ -27,3 +29,14 @@ type Controller interface {
 type Service interface {
 	Initialize(db *infra.Database) error
 }
+
+// Starter 由需要在后台运行的 Service 实现，在所有 Service 初始化完成后调用，
+// 避免后台任务访问尚未初始化的 Service 或尚未创建的表
+type Starter interface {
+	Start() error
+}
+
+// Stopper 由需要在服务关闭时释放资源的 Service 实现
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
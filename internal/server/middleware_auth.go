@@ -16,6 +16,7 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -34,6 +35,8 @@ func skipAuthCheck(path string) bool {
 	skipPaths := []string{
 		"/api/v1/login/*",
 		"/api/v1/monitor/*/daily/public",
+		// 探测点接口由 AgentAuthCheck 鉴权
+		"/api/v1/agent/*",
 	}
 
 	for _, p := range skipPaths {
@@ -73,3 +76,23 @@ func AuthCheck() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// AgentAuthCheck 校验探测点 token，并将对应的探测点写入请求上下文
+func AgentAuthCheck(tokens map[string]string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := strings.TrimPrefix(ctx.GetHeader(authorizationHeader), "Bearer ")
+		if len(token) > 0 {
+			for location, t := range tokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+					ctx.Request = ctx.Request.WithContext(
+						context.WithValue(ctx.Request.Context(), config.ContextKeyLocation, location),
+					)
+					ctx.Next()
+					return
+				}
+			}
+		}
+		controller.Reply(ctx, controller.CodeNotAuthorized, nil)
+		ctx.Abort()
+	}
+}
@@ -35,8 +35,9 @@ import (
 )
 
 type Server struct {
-	ctx    context.Context
-	server *http.Server
+	ctx      context.Context
+	server   *http.Server
+	services []Service
 }
 
 //go:embed static
@@ -72,11 +73,17 @@ func New(cfg *config.Config) (*Server, error) {
 		return nil, err
 	}
 
-	monitorService := service.NewMonitorService()
+	notificationService := service.NewNotificationService()
+	monitorService := service.NewMonitorService(notificationService)
 	userService := service.NewUserService()
+	maintenanceService := service.NewMaintenanceService()
+	incidentService := service.NewIncidentService()
 	services := []Service{
 		monitorService,
 		userService,
+		maintenanceService,
+		incidentService,
+		notificationService,
 	}
 
 	for _, svc := range services {
@@ -85,18 +92,31 @@ func New(cfg *config.Config) (*Server, error) {
 		}
 	}
 
+	for _, svc := range services {
+		if starter, ok := svc.(Starter); ok {
+			if err := starter.Start(); err != nil {
+				return nil, fmt.Errorf("failed to start service: %w", err)
+			}
+		}
+	}
+
 	controllers := []Controller{
 		controller.NewMonitorController(monitorService),
 		controller.NewUserController(userService),
+		controller.NewMaintenanceController(maintenanceService),
+		controller.NewIncidentController(incidentService),
+		controller.NewNotificationController(notificationService),
 	}
 
 	for _, ctrl := range controllers {
 		ctrl.RegisterRoute(api)
 	}
+	controller.NewAgentController(monitorService).RegisterRoute(api.Group("", AgentAuthCheck(cfg.Server.AgentTokens)))
 
 	return &Server{
-		ctx:    ctx,
-		server: server,
+		ctx:      ctx,
+		server:   server,
+		services: services,
 	}, nil
 }
 
@@ -134,5 +154,13 @@ func (s *Server) Shutdown() error {
 		return fmt.Errorf("server shutdown failed: %w", err)
 	}
 
+	for _, svc := range s.services {
+		if stopper, ok := svc.(Stopper); ok {
+			if err := stopper.Stop(ctx); err != nil {
+				return fmt.Errorf("service stop failed: %w", err)
+			}
+		}
+	}
+
 	return nil
 }
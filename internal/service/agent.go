@@ -0,0 +1,243 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/toodofun/pulse/internal/config"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+// AgentService 为 agent 模式的运行时：从中心服务拉取分配给本探测点的服务，
+// 在本地执行检查并批量上报检查结果
+type AgentService struct {
+	cfg        config.Agent
+	cronJitter time.Duration
+	client     *http.Client
+	cron       *cron.Cron
+	dispatcher *Dispatcher
+
+	// 只在 Run 所在的 goroutine 中访问
+	jobs map[string]*cronJob
+
+	mu     sync.Mutex
+	buffer []*model.Record
+}
+
+func NewAgentService(cfg *config.Config) *AgentService {
+	return &AgentService{
+		cfg:        cfg.Agent,
+		cronJitter: cfg.Scheduler.CronJitter,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		cron:       cron.New(cron.WithSeconds()),
+		dispatcher: NewDispatcher(cfg.Scheduler),
+		jobs:       make(map[string]*cronJob),
+	}
+}
+
+// Run 阻塞运行直到 ctx 被取消，退出前尽量上报剩余的检查结果
+func (a *AgentService) Run(ctx context.Context) error {
+	if a.cfg.Server == "" || a.cfg.Location == "" || a.cfg.Token == "" {
+		return errors.New("agent server, location and token are required")
+	}
+
+	a.cron.Start()
+	logrus.Infof("agent for location %s connecting to %s", a.cfg.Location, a.cfg.Server)
+
+	if err := a.sync(ctx, false); err != nil {
+		logrus.Errorf("failed to sync services: %v", err)
+	}
+
+	syncTicker := time.NewTicker(a.cfg.SyncInterval)
+	defer syncTicker.Stop()
+	pushTicker := time.NewTicker(a.cfg.PushInterval)
+	defer pushTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return a.stop()
+		case <-syncTicker.C:
+			if err := a.sync(ctx, true); err != nil {
+				logrus.Errorf("failed to sync services: %v", err)
+			}
+		case <-pushTicker.C:
+			if err := a.push(ctx); err != nil {
+				logrus.Errorf("failed to push records: %v", err)
+			}
+		}
+	}
+}
+
+func (a *AgentService) stop() error {
+	<-a.cron.Stop().Done()
+	for id := range a.jobs {
+		a.unschedule(id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := a.dispatcher.Wait(ctx); err != nil {
+		logrus.Warnf("failed to wait for running checks: %v", err)
+	}
+	return a.push(ctx)
+}
+
+// sync 拉取分配给本探测点的服务，新增、更新或移除本地的调度任务
+func (a *AgentService) sync(ctx context.Context, immediate bool) error {
+	var services []*model.Service
+	if err := a.call(ctx, http.MethodGet, "/agent/services", nil, &services); err != nil {
+		return err
+	}
+
+	assigned := make(map[string]struct{}, len(services))
+	for _, service := range services {
+		assigned[service.ID] = struct{}{}
+		if job, ok := a.jobs[service.ID]; ok && job.service.UpdatedAt.Equal(service.UpdatedAt) {
+			continue
+		}
+		if err := a.schedule(ctx, service, immediate); err != nil {
+			logrus.Errorf("failed to schedule service %s: %v", service.Title, err)
+		}
+	}
+
+	for id := range a.jobs {
+		if _, ok := assigned[id]; !ok {
+			a.unschedule(id)
+		}
+	}
+	return nil
+}
+
+func (a *AgentService) schedule(ctx context.Context, service *model.Service, immediate bool) error {
+	schedule, err := parseSchedule(service, a.cronJitter)
+	if err != nil {
+		return err
+	}
+
+	a.unschedule(service.ID)
+	taskCtx, cancel := context.WithCancel(ctx)
+	task := NewReportTask(taskCtx, service, a.enqueue)
+	id := a.cron.Schedule(schedule, cron.FuncJob(func() {
+		a.dispatcher.Submit(task)
+	}))
+	a.jobs[service.ID] = &cronJob{id: id, cancel: cancel, service: service}
+	logrus.Infof("Job for service %s added with ID %d", service.Title, id)
+
+	if immediate {
+		a.dispatcher.Submit(task)
+	}
+	return nil
+}
+
+func (a *AgentService) unschedule(serviceID string) {
+	if job, ok := a.jobs[serviceID]; ok {
+		delete(a.jobs, serviceID)
+		a.cron.Remove(job.id)
+		job.cancel()
+		logrus.Infof("Job for service %s removed", job.service.Title)
+	}
+}
+
+// enqueue 缓存检查结果等待上报，超出 BufferSize 时丢弃最早的结果
+func (a *AgentService) enqueue(r *model.Record) {
+	r.Location = a.cfg.Location
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.buffer = append(a.buffer, r)
+	if over := len(a.buffer) - a.cfg.BufferSize; over > 0 {
+		logrus.Warnf("record buffer is full, drop %d records", over)
+		a.buffer = append([]*model.Record(nil), a.buffer[over:]...)
+	}
+}
+
+// push 上报缓存的检查结果，失败时放回缓存等待下次上报
+func (a *AgentService) push(ctx context.Context) error {
+	a.mu.Lock()
+	records := a.buffer
+	a.buffer = nil
+	a.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := a.call(ctx, http.MethodPost, "/agent/records", records, nil); err != nil {
+		a.mu.Lock()
+		a.buffer = append(records, a.buffer...)
+		if over := len(a.buffer) - a.cfg.BufferSize; over > 0 {
+			a.buffer = append([]*model.Record(nil), a.buffer[over:]...)
+		}
+		a.mu.Unlock()
+		return err
+	}
+	logrus.Debugf("pushed %d records", len(records))
+	return nil
+}
+
+// call 调用中心服务的接口，out 不为空时将响应中的 data 解析到 out
+func (a *AgentService) call(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(a.cfg.Server, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.cfg.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Code int             `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("%s %s: unexpected response with status %d: %w", method, path, resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || res.Code != 0 {
+		return fmt.Errorf("%s %s: %s", method, path, res.Msg)
+	}
+	if out != nil && len(res.Data) > 0 {
+		return json.Unmarshal(res.Data, out)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const (
+	// staleChecks 探测点超过该数量的检查周期没有更新状态时视为失联
+	staleChecks = 3
+	// minStaleAfter 为判定失联的最短时间，为 agent 缓冲上报留出余量
+	minStaleAfter = time.Minute
+)
+
+func checkLocationPolicy(service *model.Service) error {
+	switch service.LocationPolicy {
+	case "":
+		service.LocationPolicy = model.LocationPolicyMajority
+	case model.LocationPolicyAny, model.LocationPolicyMajority, model.LocationPolicyAll:
+	default:
+		return fmt.Errorf("invalid location policy %q", service.LocationPolicy)
+	}
+	return nil
+}
+
+// decideStatus 根据各探测点的状态和服务的 LocationPolicy 决定服务的整体状态，返回整体状态和 down 的探测点数量。
+// 只统计服务当前配置的探测点，尚未确认状态的探测点不参与判定。
+func decideStatus(service *model.Service, states []model.LocationState) (string, int) {
+	locations := make(map[string]struct{})
+	for _, location := range service.ProbeLocations() {
+		locations[location] = struct{}{}
+	}
+
+	known, down := 0, 0
+	for _, state := range states {
+		if _, ok := locations[state.Location]; !ok || state.Status == "" {
+			continue
+		}
+		known++
+		if state.Status == model.StatusDown {
+			down++
+		}
+	}
+	if known == 0 {
+		return "", 0
+	}
+
+	var isDown bool
+	switch service.LocationPolicy {
+	case model.LocationPolicyAny:
+		isDown = down > 0
+	case model.LocationPolicyAll:
+		isDown = down == known
+	default:
+		isDown = down*2 > known
+	}
+
+	if isDown {
+		return model.StatusDown, down
+	}
+	return model.StatusUp, down
+}
+
+// freshStates 过滤掉失联探测点的状态，避免停止上报的 agent 保留的旧状态一直影响整体状态
+func freshStates(service *model.Service, states []model.LocationState, now time.Time) []model.LocationState {
+	period := time.Duration(service.Interval) * time.Second
+	if schedule, err := parseSchedule(service, 0); err == nil {
+		next := schedule.Next(now)
+		period = schedule.Next(next).Sub(next)
+	}
+	staleAfter := max(staleChecks*period, minStaleAfter)
+
+	fresh := make([]model.LocationState, 0, len(states))
+	for _, state := range states {
+		if now.Sub(state.UpdatedAt) <= staleAfter {
+			fresh = append(fresh, state)
+		}
+	}
+	return fresh
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+func TestDecideStatus(t *testing.T) {
+	states := func(statuses ...string) []model.LocationState {
+		locations := []string{"eu", "us", "ap"}
+		res := make([]model.LocationState, 0, len(statuses))
+		for i, status := range statuses {
+			res = append(res, model.LocationState{Location: locations[i], Status: status})
+		}
+		return res
+	}
+	up, down := model.StatusUp, model.StatusDown
+
+	tests := []struct {
+		name      string
+		locations []string
+		policy    string
+		states    []model.LocationState
+		want      string
+		wantDown  int
+	}{
+		{
+			name:     "local only",
+			policy:   model.LocationPolicyMajority,
+			states:   []model.LocationState{{Location: model.LocationLocal, Status: down}},
+			want:     down,
+			wantDown: 1,
+		},
+		{
+			name:      "no state yet",
+			locations: []string{"eu", "us"},
+			policy:    model.LocationPolicyAny,
+			want:      "",
+		},
+		{
+			name:      "any with one down",
+			locations: []string{"eu", "us", "ap"},
+			policy:    model.LocationPolicyAny,
+			states:    states(up, down, up),
+			want:      down,
+			wantDown:  1,
+		},
+		{
+			name:      "majority with one down",
+			locations: []string{"eu", "us", "ap"},
+			policy:    model.LocationPolicyMajority,
+			states:    states(up, down, up),
+			want:      up,
+			wantDown:  1,
+		},
+		{
+			name:      "majority with two down",
+			locations: []string{"eu", "us", "ap"},
+			policy:    model.LocationPolicyMajority,
+			states:    states(down, down, up),
+			want:      down,
+			wantDown:  2,
+		},
+		{
+			name:      "majority with half down",
+			locations: []string{"eu", "us"},
+			policy:    model.LocationPolicyMajority,
+			states:    states(down, up),
+			want:      up,
+			wantDown:  1,
+		},
+		{
+			name:      "all with two down",
+			locations: []string{"eu", "us", "ap"},
+			policy:    model.LocationPolicyAll,
+			states:    states(down, down, up),
+			want:      up,
+			wantDown:  2,
+		},
+		{
+			name:      "all down ignoring unknown location",
+			locations: []string{"eu", "us", "ap"},
+			policy:    model.LocationPolicyAll,
+			states:    states(down, down, ""),
+			want:      down,
+			wantDown:  2,
+		},
+		{
+			name:      "removed location is ignored",
+			locations: []string{"eu"},
+			policy:    model.LocationPolicyAny,
+			states:    states(up, down),
+			want:      up,
+			wantDown:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &model.Service{Locations: tt.locations, LocationPolicy: tt.policy}
+			got, gotDown := decideStatus(service, tt.states)
+			if got != tt.want || gotDown != tt.wantDown {
+				t.Errorf("decideStatus() = %q, %d, want %q, %d", got, gotDown, tt.want, tt.wantDown)
+			}
+		})
+	}
+}
+
+func TestFreshStates(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	states := []model.LocationState{
+		{Location: "eu", Status: model.StatusUp, UpdatedAt: now.Add(-10 * time.Second)},
+		{Location: "us", Status: model.StatusDown, UpdatedAt: now.Add(-5 * time.Minute)},
+		{Location: "ap", Status: model.StatusDown, UpdatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name    string
+		service *model.Service
+		want    []string
+	}{
+		{name: "short interval uses minimum", service: &model.Service{Interval: 10}, want: []string{"eu"}},
+		{name: "three intervals", service: &model.Service{Interval: 120}, want: []string{"eu", "us"}},
+		{name: "cron period", service: &model.Service{Cron: "0 0 * * * *"}, want: []string{"eu", "us", "ap"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, state := range freshStates(tt.service, states, now) {
+				got = append(got, state.Location)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("freshStates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 停止上报的探测点保留的 down 不再使 any 策略的服务保持 down
+	service := &model.Service{Interval: 60, Locations: []string{"eu", "ap"}, LocationPolicy: model.LocationPolicyAny}
+	if got, _ := decideStatus(service, freshStates(service, states, now)); got != model.StatusUp {
+		t.Errorf("decideStatus() with stale location = %q, want %q", got, model.StatusUp)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/toodofun/pulse/internal/infra"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+// checkParents 校验上游服务属于同一用户，且加入后依赖图中不存在环
+func (s *MonitorService) checkParents(service *model.Service) error {
+	if len(service.Parents) == 0 {
+		return nil
+	}
+
+	var services []*model.Service
+	if err := s.db.Where(&model.Service{CreatedBy: service.CreatedBy}).Find(&services).Error; err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	graph := make(map[string][]string, len(services)+1)
+	titles := make(map[string]string, len(services)+1)
+	for _, svc := range services {
+		graph[svc.ID] = svc.Parents
+		titles[svc.ID] = svc.Title
+	}
+
+	for i, parent := range service.Parents {
+		if parent == service.ID {
+			return errors.New("service cannot depend on itself")
+		}
+		if slices.Contains(service.Parents[:i], parent) {
+			return fmt.Errorf("duplicate parent %s", parent)
+		}
+		if _, ok := graph[parent]; !ok {
+			return fmt.Errorf("parent service %s does not exist", parent)
+		}
+	}
+
+	// 新服务尚未分配 ID，不可能被其他服务依赖
+	if service.ID == "" {
+		return nil
+	}
+	graph[service.ID] = service.Parents
+	titles[service.ID] = service.Title
+	if cycle := findCycle(graph, service.ID); len(cycle) > 0 {
+		names := make([]string, 0, len(cycle))
+		for _, id := range cycle {
+			names = append(names, titles[id])
+		}
+		return fmt.Errorf("dependency cycle detected: %s", strings.Join(names, " -> "))
+	}
+	return nil
+}
+
+// findCycle 沿上游方向查找经过 start 的环，返回环上的服务 ID，首尾相同；不存在时返回 nil
+func findCycle(graph map[string][]string, start string) []string {
+	var (
+		path    []string
+		visited = make(map[string]bool)
+		visit   func(id string) bool
+	)
+	visit = func(id string) bool {
+		path = append(path, id)
+		for _, parent := range graph[id] {
+			if parent == start {
+				path = append(path, parent)
+				return true
+			}
+			if visited[parent] {
+				continue
+			}
+			visited[parent] = true
+			if visit(parent) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	if visit(start) {
+		return path
+	}
+	return nil
+}
+
+// dependencyDown 判断服务的任一上游（包括间接上游）是否已确认 down
+func dependencyDown(db *infra.Database, service *model.Service) (bool, error) {
+	visited := map[string]bool{service.ID: true}
+	queue := append([]string(nil), service.Parents...)
+	for len(queue) > 0 {
+		ids := make([]string, 0, len(queue))
+		for _, id := range queue {
+			if !visited[id] {
+				visited[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		var down int64
+		if err := db.Model(&model.ServiceState{}).
+			Where("service_id IN ? AND status = ?", ids, model.StatusDown).
+			Count(&down).Error; err != nil {
+			return false, err
+		}
+		if down > 0 {
+			return true, nil
+		}
+
+		var parents []*model.Service
+		if err := db.Where("id IN ?", ids).Find(&parents).Error; err != nil {
+			return false, err
+		}
+		queue = queue[:0]
+		for _, parent := range parents {
+			queue = append(queue, parent.Parents...)
+		}
+	}
+	return false, nil
+}
+
+// GetDependencyGraph 返回用户所有服务的依赖关系及其状态
+func (s *MonitorService) GetDependencyGraph(operator string) (*model.DependencyGraph, error) {
+	var services []*model.Service
+	if err := s.db.Where(&model.Service{CreatedBy: operator}).Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	ids := make([]string, 0, len(services))
+	for _, service := range services {
+		ids = append(ids, service.ID)
+	}
+	var states []model.ServiceState
+	if err := s.db.Where("service_id IN ?", ids).Find(&states).Error; err != nil {
+		return nil, fmt.Errorf("failed to list service states: %w", err)
+	}
+	statusMap := make(map[string]string, len(states))
+	for _, state := range states {
+		statusMap[state.ServiceID] = state.Status
+	}
+
+	graph := &model.DependencyGraph{
+		Nodes: make([]model.DependencyNode, 0, len(services)),
+		Edges: make([]model.DependencyEdge, 0),
+	}
+	exists := make(map[string]bool, len(services))
+	for _, service := range services {
+		exists[service.ID] = true
+	}
+	for _, service := range services {
+		graph.Nodes = append(graph.Nodes, model.DependencyNode{
+			ID:     service.ID,
+			Title:  service.Title,
+			Status: statusMap[service.ID],
+		})
+		for _, parent := range service.Parents {
+			// 上游服务可能已被删除
+			if exists[parent] {
+				graph.Edges = append(graph.Edges, model.DependencyEdge{Parent: parent, Child: service.ID})
+			}
+		}
+	}
+	return graph, nil
+}
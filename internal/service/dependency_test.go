@@ -0,0 +1,74 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph map[string][]string
+		start string
+		want  []string
+	}{
+		{
+			name:  "no parents",
+			graph: map[string][]string{"a": nil},
+			start: "a",
+		},
+		{
+			name:  "chain",
+			graph: map[string][]string{"a": {"b"}, "b": {"c"}, "c": nil},
+			start: "a",
+		},
+		{
+			name:  "diamond",
+			graph: map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}, "d": nil},
+			start: "a",
+		},
+		{
+			name:  "self",
+			graph: map[string][]string{"a": {"a"}},
+			start: "a",
+			want:  []string{"a", "a"},
+		},
+		{
+			name:  "indirect",
+			graph: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}},
+			start: "a",
+			want:  []string{"a", "b", "c", "a"},
+		},
+		{
+			name:  "cycle not through start",
+			graph: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"b"}},
+			start: "a",
+		},
+		{
+			name:  "missing parent",
+			graph: map[string][]string{"a": {"deleted"}},
+			start: "a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findCycle(tt.graph, tt.start); !slices.Equal(got, tt.want) {
+				t.Errorf("findCycle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/toodofun/pulse/internal/config"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+// Dispatcher 将到期的检查任务放入按检查类型划分的队列，由有界的 worker 池执行。
+// 每种类型的 worker 数量受 TypeLimits 限制，所有类型共享 Workers 个全局执行槽位。
+type Dispatcher struct {
+	cfg    config.Scheduler
+	global chan struct{}
+
+	mu     sync.Mutex
+	queues map[model.CheckerType]chan *dispatchItem
+
+	// 正在排队或执行中的任务，避免同一个任务堆积
+	pending sync.Map
+
+	running  atomic.Int64
+	executed atomic.Uint64
+	dropped  atomic.Uint64
+	skipped  atomic.Uint64
+	lastLag  atomic.Int64
+	maxLag   atomic.Int64
+}
+
+type dispatchItem struct {
+	task       *CheckTask
+	enqueuedAt time.Time
+}
+
+type DispatcherStats struct {
+	Workers    int                       `json:"workers"`
+	Running    int64                     `json:"running"`
+	Queued     int                       `json:"queued"`
+	QueueSize  int                       `json:"queueSize"`
+	Queues     map[model.CheckerType]int `json:"queues"`
+	TypeLimits map[string]int            `json:"typeLimits"`
+	Executed   uint64                    `json:"executed"`
+	Dropped    uint64                    `json:"dropped"`
+	Skipped    uint64                    `json:"skipped"`
+	LastLag    int64                     `json:"lastLag"` // 毫秒
+	MaxLag     int64                     `json:"maxLag"`  // 毫秒
+}
+
+func NewDispatcher(cfg config.Scheduler) *Dispatcher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	return &Dispatcher{
+		cfg:    cfg,
+		global: make(chan struct{}, cfg.Workers),
+		queues: make(map[model.CheckerType]chan *dispatchItem),
+	}
+}
+
+// Submit 将任务放入队列，不会阻塞调用方；队列已满或同一服务已在排队时任务被丢弃
+func (d *Dispatcher) Submit(task *CheckTask) bool {
+	if _, loaded := d.pending.LoadOrStore(task, struct{}{}); loaded {
+		d.skipped.Add(1)
+		logrus.Debugf("service %s is still queued or running, skip", task.service.Title)
+		return false
+	}
+
+	select {
+	case d.queue(task.service.Type) <- &dispatchItem{task: task, enqueuedAt: time.Now()}:
+		return true
+	default:
+		d.pending.Delete(task)
+		d.dropped.Add(1)
+		logrus.Warnf("check queue for type %s is full, drop check of service %s", task.service.Type, task.service.Title)
+		return false
+	}
+}
+
+func (d *Dispatcher) queue(t model.CheckerType) chan *dispatchItem {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if q, ok := d.queues[t]; ok {
+		return q
+	}
+
+	q := make(chan *dispatchItem, d.cfg.QueueSize)
+	d.queues[t] = q
+
+	workers := d.cfg.Workers
+	if limit, ok := d.cfg.TypeLimits[string(t)]; ok && limit > 0 && limit < workers {
+		workers = limit
+	}
+	for i := 0; i < workers; i++ {
+		go d.work(q)
+	}
+	logrus.Infof("dispatcher queue for type %s started with %d workers", t, workers)
+
+	return q
+}
+
+func (d *Dispatcher) work(q chan *dispatchItem) {
+	for item := range q {
+		d.global <- struct{}{}
+		d.run(item)
+		<-d.global
+	}
+}
+
+func (d *Dispatcher) run(item *dispatchItem) {
+	defer d.pending.Delete(item.task)
+
+	// 任务已被取消，直接跳过
+	if item.task.ctx.Err() != nil {
+		return
+	}
+
+	lag := time.Since(item.enqueuedAt).Milliseconds()
+	d.lastLag.Store(lag)
+	for {
+		current := d.maxLag.Load()
+		if lag <= current || d.maxLag.CompareAndSwap(current, lag) {
+			break
+		}
+	}
+
+	d.running.Add(1)
+	defer d.running.Add(-1)
+	item.task.Run()
+	d.executed.Add(1)
+}
+
+// Wait 等待执行中的任务结束，ctx 超时则返回错误
+func (d *Dispatcher) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for d.running.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %d running checks: %w", d.running.Load(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) Stats() *DispatcherStats {
+	stats := &DispatcherStats{
+		Workers:    d.cfg.Workers,
+		Running:    d.running.Load(),
+		QueueSize:  d.cfg.QueueSize,
+		Queues:     make(map[model.CheckerType]int),
+		TypeLimits: d.cfg.TypeLimits,
+		Executed:   d.executed.Load(),
+		Dropped:    d.dropped.Load(),
+		Skipped:    d.skipped.Load(),
+		LastLag:    d.lastLag.Load(),
+		MaxLag:     d.maxLag.Load(),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for t, q := range d.queues {
+		stats.Queues[t] = len(q)
+		stats.Queued += len(q)
+	}
+
+	return stats
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/toodofun/pulse/internal/config"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+// updateFlapping 统计窗口内服务状态的变化次数，超过阈值时标记为抖动，窗口内没有变化时恢复稳定，
+// 进入或退出抖动时返回对应的通知事件
+func updateFlapping(tx *gorm.DB, service *model.Service, state *model.ServiceState, r *model.Record,
+	cfg config.Flapping) (*model.Alert, error) {
+	if cfg.Threshold <= 0 {
+		if state.Flapping {
+			state.Flapping = false
+			state.FlappingSince = nil
+		}
+		return nil, nil
+	}
+
+	var changes int64
+	if err := tx.Model(&model.IncidentEvent{}).
+		Joins("JOIN incidents ON incidents.id = incident_events.incident_id").
+		Where("incidents.service_id = ? AND incident_events.type IN ? AND incident_events.created_at >= ?",
+			service.ID, []string{model.IncidentEventOpened, model.IncidentEventResolved}, r.MonitorAt.Add(-cfg.Window)).
+		Count(&changes).Error; err != nil {
+		return nil, err
+	}
+
+	alertType, eventType := "", ""
+	switch {
+	case !state.Flapping && changes > int64(cfg.Threshold):
+		since := r.MonitorAt
+		state.Flapping = true
+		state.FlappingSince = &since
+		alertType, eventType = model.AlertFlapping, model.IncidentEventFlapping
+	case state.Flapping && changes == 0:
+		state.Flapping = false
+		state.FlappingSince = nil
+		alertType, eventType = model.AlertStable, model.IncidentEventStable
+	default:
+		return nil, nil
+	}
+
+	// 事件记录在最近一次故障的时间线上
+	var incident model.Incident
+	if err := tx.Where("service_id = ?", service.ID).
+		Order("started_at DESC").
+		Limit(1).
+		Find(&incident).Error; err != nil {
+		return nil, err
+	}
+	if incident.ID == 0 {
+		return newAlert(alertType, service, nil, r), nil
+	}
+
+	message := fmt.Sprintf("%d state changes within %s, current status %s", changes, cfg.Window, state.Status)
+	if err := addIncidentEvent(tx, incident.ID, eventType, message, "", r.MonitorAt); err != nil {
+		return nil, err
+	}
+	fitDuration(&incident)
+	return newAlert(alertType, service, &incident, r), nil
+}
@@ -0,0 +1,368 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/toodofun/pulse/internal/infra"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+type IncidentService struct {
+	db *infra.Database
+}
+
+func NewIncidentService() *IncidentService {
+	return &IncidentService{}
+}
+
+// ListIncidents 返回在 [since, until) 内开始的故障及统计，包括用户服务的故障和指派给用户的故障，
+// serviceID 为空时不按服务过滤
+func (s *IncidentService) ListIncidents(operator, serviceID string, since, until time.Time) (*model.IncidentReport, error) {
+	// 已删除服务的故障仍计入统计
+	query := s.db.Unscoped().Model(&model.Service{}).Where(&model.Service{CreatedBy: operator})
+	if serviceID != "" {
+		query = query.Where("id = ?", serviceID)
+	}
+	var ids []string
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	assigned := s.db.Where("assignee = ?", operator)
+	if serviceID != "" {
+		assigned = assigned.Where("service_id = ?", serviceID)
+	}
+
+	report := &model.IncidentReport{Incidents: make([]*model.Incident, 0)}
+	if err := s.db.Where(s.db.Where("service_id IN ?", ids).Or(assigned)).
+		Where("started_at >= ? AND started_at < ?", since, until).
+		Order("started_at DESC").
+		Find(&report.Incidents).Error; err != nil {
+		return nil, fmt.Errorf("failed to list incidents: %w", err)
+	}
+
+	for _, incident := range report.Incidents {
+		fitDuration(incident)
+		report.TotalDuration += incident.Duration
+	}
+	report.Count = len(report.Incidents)
+	return report, nil
+}
+
+func (s *IncidentService) GetIncident(id uint64, operator string) (*model.Incident, error) {
+	incident, err := s.findIncident(id, operator, false)
+	if err != nil {
+		return nil, err
+	}
+	fitDuration(incident)
+	return incident, nil
+}
+
+// findIncident 查找故障并校验权限，服务的创建者和故障的指派人可以处理故障，ownerOnly 为 true 时只允许创建者
+func (s *IncidentService) findIncident(id uint64, operator string, ownerOnly bool) (*model.Incident, error) {
+	var incident model.Incident
+	if err := s.db.First(&incident, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("failed to find incident")
+	}
+
+	var service model.Service
+	if err := s.db.Unscoped().First(&service, "id = ?", incident.ServiceID).Error; err != nil {
+		return nil, fmt.Errorf("failed to find service")
+	}
+	if service.CreatedBy != operator && (ownerOnly || incident.Assignee != operator) {
+		return nil, fmt.Errorf("no permission")
+	}
+	return &incident, nil
+}
+
+func (s *IncidentService) Acknowledge(id uint64, operator string) error {
+	incident, err := s.findIncident(id, operator, false)
+	if err != nil {
+		return err
+	}
+	if incident.Status != model.IncidentOpen {
+		return fmt.Errorf("incident already resolved")
+	}
+	if incident.AcknowledgedAt != nil {
+		return fmt.Errorf("incident already acknowledged by %s", incident.AcknowledgedBy)
+	}
+
+	now := time.Now()
+	incident.AcknowledgedAt = &now
+	incident.AcknowledgedBy = operator
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(incident).Error; err != nil {
+			return fmt.Errorf("failed to acknowledge incident: %w", err)
+		}
+		return addIncidentEvent(tx, incident.ID, model.IncidentEventAcknowledged, "", operator, now)
+	})
+}
+
+// Assign 指派故障的处理人，只有服务的创建者可以指派
+func (s *IncidentService) Assign(id uint64, assignee, operator string) error {
+	incident, err := s.findIncident(id, operator, true)
+	if err != nil {
+		return err
+	}
+
+	if assignee != "" {
+		var count int64
+		if err = s.db.Model(&model.User{}).Where("username = ?", assignee).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to find user: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("user %s not found", assignee)
+		}
+	}
+
+	incident.Assignee = assignee
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(incident).Error; err != nil {
+			return fmt.Errorf("failed to assign incident: %w", err)
+		}
+		return addIncidentEvent(tx, incident.ID, model.IncidentEventAssigned, assignee, operator, time.Now())
+	})
+}
+
+func (s *IncidentService) AddNote(id uint64, message, operator string) (*model.IncidentEvent, error) {
+	if message == "" {
+		return nil, fmt.Errorf("note cannot be empty")
+	}
+	if len(message) > 1024 {
+		return nil, fmt.Errorf("note cannot be longer than 1024 characters")
+	}
+
+	incident, err := s.findIncident(id, operator, false)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &model.IncidentEvent{
+		IncidentID: incident.ID,
+		Type:       model.IncidentEventNote,
+		Message:    message,
+		CreatedBy:  operator,
+	}
+	if err = s.db.Create(event).Error; err != nil {
+		return nil, fmt.Errorf("failed to add note: %w", err)
+	}
+	return event, nil
+}
+
+// GetTimeline 返回故障的所有事件，按时间顺序排列
+func (s *IncidentService) GetTimeline(id uint64, operator string) ([]*model.IncidentEvent, error) {
+	incident, err := s.findIncident(id, operator, false)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*model.IncidentEvent, 0)
+	if err = s.db.Where("incident_id = ?", incident.ID).
+		Order("created_at, id").
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list incident events: %w", err)
+	}
+	return events, nil
+}
+
+func (s *IncidentService) Initialize(db *infra.Database) error {
+	if err := db.AutoMigrate(&model.Incident{}, &model.IncidentEvent{}); err != nil {
+		return err
+	}
+	s.db = db
+	return nil
+}
+
+// fitDuration 计算未解决故障截至当前的时长
+func fitDuration(incident *model.Incident) {
+	if incident.Status == model.IncidentOpen {
+		incident.Duration = int64(time.Since(incident.StartedAt).Seconds())
+	}
+}
+
+// trackIncident 根据服务的整体状态打开或解决故障：down 且没有未解决的故障时打开，up 时解决未解决的故障。
+// 以未解决的故障而不是状态变化为准，状态曾经未知时也不会遗漏或重复打开故障。故障打开或解决时返回对应的通知事件
+func trackIncident(tx *gorm.DB, service *model.Service, state *model.ServiceState,
+	states []model.LocationState, r *model.Record) (*model.Alert, error) {
+	incident, err := openIncident(tx, service.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case state.Status == model.StatusDown && incident == nil:
+		incident = &model.Incident{
+			ServiceID: service.ID,
+			Status:    model.IncidentOpen,
+			StartedAt: r.MonitorAt,
+			Message:   r.Message,
+			Locations: downLocations(service, states),
+		}
+		// 故障从连续失败的第一次开始计算
+		first, err := firstFailure(tx, service.ID, r.Location, r.MonitorAt)
+		if err != nil {
+			return nil, err
+		}
+		if first != nil {
+			incident.StartedAt = first.MonitorAt
+			incident.Message = first.Message
+		}
+		if err = tx.Create(incident).Error; err != nil {
+			return nil, err
+		}
+		if err = addIncidentEvent(tx, incident.ID, model.IncidentEventOpened, incident.Message, "", incident.StartedAt); err != nil {
+			return nil, err
+		}
+		return newAlert(model.AlertIncidentOpened, service, incident, r), nil
+
+	case state.Status == model.StatusDown:
+		// 故障期间新 down 的探测点也计入受影响的探测点
+		changed := false
+		for _, location := range downLocations(service, states) {
+			if slices.Contains(incident.Locations, location) {
+				continue
+			}
+			incident.Locations = append(incident.Locations, location)
+			changed = true
+			message := fmt.Sprintf("location %s is down", location)
+			if err = addIncidentEvent(tx, incident.ID, model.IncidentEventLocationDown, message, "", r.MonitorAt); err != nil {
+				return nil, err
+			}
+		}
+		if !changed {
+			return nil, nil
+		}
+		return nil, tx.Save(incident).Error
+
+	case state.Status == model.StatusUp && incident != nil:
+		resolvedAt := r.MonitorAt
+		incident.Status = model.IncidentResolved
+		incident.ResolvedAt = &resolvedAt
+		incident.Duration = int64(resolvedAt.Sub(incident.StartedAt).Seconds())
+		if err = tx.Save(incident).Error; err != nil {
+			return nil, err
+		}
+		if err = addIncidentEvent(tx, incident.ID, model.IncidentEventResolved, r.Message, "", resolvedAt); err != nil {
+			return nil, err
+		}
+		return newAlert(model.AlertIncidentResolved, service, incident, r), nil
+	}
+	return nil, nil
+}
+
+// closeIncident 在服务被停用或删除时清除其状态并解决未解决的故障，reason 记录在故障时间线上。
+// 服务之后不再检查，故障无法再由检查解决
+func closeIncident(tx *gorm.DB, service *model.Service, reason, operator string) (*model.Alert, error) {
+	if err := deleteStates(tx, service.ID); err != nil {
+		return nil, err
+	}
+
+	incident, err := openIncident(tx, service.ID)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+	now := time.Now()
+	incident.Status = model.IncidentResolved
+	incident.ResolvedAt = &now
+	incident.Duration = int64(now.Sub(incident.StartedAt).Seconds())
+	if err = tx.Save(incident).Error; err != nil {
+		return nil, err
+	}
+	if err = addIncidentEvent(tx, incident.ID, model.IncidentEventResolved, reason, operator, now); err != nil {
+		return nil, err
+	}
+	return &model.Alert{
+		Type:     model.AlertIncidentResolved,
+		Service:  service,
+		Incident: incident,
+		At:       now,
+	}, nil
+}
+
+func newAlert(alertType string, service *model.Service, incident *model.Incident, r *model.Record) *model.Alert {
+	return &model.Alert{
+		Type:     alertType,
+		Service:  service,
+		Incident: incident,
+		Record:   r,
+		At:       r.MonitorAt,
+	}
+}
+
+func addIncidentEvent(tx *gorm.DB, incidentID uint64, eventType, message, operator string, at time.Time) error {
+	return tx.Create(&model.IncidentEvent{
+		IncidentID: incidentID,
+		Type:       eventType,
+		Message:    message,
+		CreatedBy:  operator,
+		CreatedAt:  at,
+	}).Error
+}
+
+func openIncident(tx *gorm.DB, serviceID string) (*model.Incident, error) {
+	var incident model.Incident
+	if err := tx.Where("service_id = ? AND status = ?", serviceID, model.IncidentOpen).
+		Order("started_at DESC").
+		Limit(1).
+		Find(&incident).Error; err != nil {
+		return nil, err
+	}
+	if incident.ID == 0 {
+		return nil, nil
+	}
+	return &incident, nil
+}
+
+// firstFailure 返回探测点在 before 之前最后一次成功之后的第一次失败
+func firstFailure(tx *gorm.DB, serviceID, location string, before time.Time) (*model.Record, error) {
+	var last model.Record
+	if err := tx.Where("service_id = ? AND location = ? AND is_success = ? AND monitor_at <= ?",
+		serviceID, location, true, before).
+		Order("monitor_at DESC").
+		Limit(1).
+		Find(&last).Error; err != nil {
+		return nil, err
+	}
+
+	query := tx.Where("service_id = ? AND location = ? AND is_success = ? AND suppress_reason = ? AND monitor_at <= ?",
+		serviceID, location, false, "", before)
+	if last.ID != 0 {
+		query = query.Where("monitor_at > ?", last.MonitorAt)
+	}
+	var first model.Record
+	if err := query.Order("monitor_at").Limit(1).Find(&first).Error; err != nil {
+		return nil, err
+	}
+	if first.ID == 0 {
+		return nil, nil
+	}
+	return &first, nil
+}
+
+func downLocations(service *model.Service, states []model.LocationState) []string {
+	res := make([]string, 0)
+	for _, state := range states {
+		if state.Status == model.StatusDown && slices.Contains(service.ProbeLocations(), state.Location) {
+			res = append(res, state.Location)
+		}
+	}
+	return res
+}
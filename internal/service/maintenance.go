@@ -0,0 +1,167 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/toodofun/pulse/internal/infra"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+type MaintenanceService struct {
+	db *infra.Database
+}
+
+func NewMaintenanceService() *MaintenanceService {
+	return &MaintenanceService{}
+}
+
+func (s *MaintenanceService) checkWindow(w *model.MaintenanceWindow) error {
+	if w.Title == "" {
+		return errors.New("maintenance title cannot be empty")
+	}
+
+	if len(w.ServiceIDs) == 0 && len(w.Tags) == 0 {
+		return errors.New("maintenance must target at least one service or tag")
+	}
+
+	for i, id := range w.ServiceIDs {
+		if slices.Contains(w.ServiceIDs[:i], id) {
+			return fmt.Errorf("duplicate service %s", id)
+		}
+	}
+	if len(w.ServiceIDs) > 0 {
+		var count int64
+		if err := s.db.Model(&model.Service{}).
+			Where("id IN ? AND created_by = ?", w.ServiceIDs, w.CreatedBy).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to find services: %w", err)
+		}
+		if int(count) != len(w.ServiceIDs) {
+			return errors.New("some services do not exist")
+		}
+	}
+
+	if (w.StartAt == nil) != (w.EndAt == nil) {
+		return errors.New("startAt and endAt must be set together")
+	}
+	if w.StartAt != nil && !w.EndAt.After(*w.StartAt) {
+		return errors.New("endAt must be after startAt")
+	}
+	if w.StartAt == nil && len(w.Windows) == 0 {
+		return errors.New("maintenance must have a time range or recurring windows")
+	}
+
+	if _, err := loadLocation(w.TimeZone); err != nil {
+		return fmt.Errorf("invalid maintenance timezone: %w", err)
+	}
+	return normalizeWindows(w.Windows)
+}
+
+func (s *MaintenanceService) ListWindows(operator string) ([]*model.MaintenanceWindow, error) {
+	var windows []*model.MaintenanceWindow
+	if err := s.db.Where(&model.MaintenanceWindow{CreatedBy: operator}).
+		Order("created_at DESC").
+		Find(&windows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	return windows, nil
+}
+
+func (s *MaintenanceService) AddWindow(w *model.MaintenanceWindow, operator string) error {
+	w.ID = ""
+	w.CreatedBy = operator
+	if err := s.checkWindow(w); err != nil {
+		return err
+	}
+
+	if err := s.db.Create(w).Error; err != nil {
+		return fmt.Errorf("failed to add maintenance window: %w", err)
+	}
+	return nil
+}
+
+func (s *MaintenanceService) UpdateWindow(w *model.MaintenanceWindow, id, operator string) error {
+	var res model.MaintenanceWindow
+	if err := s.db.First(&res, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to find maintenance window: %w", err)
+	}
+	if res.CreatedBy != operator {
+		return fmt.Errorf("no permission")
+	}
+
+	w.ID = res.ID
+	w.CreatedBy = res.CreatedBy
+	w.CreatedAt = res.CreatedAt
+	if err := s.checkWindow(w); err != nil {
+		return err
+	}
+
+	if err := s.db.Save(w).Error; err != nil {
+		return fmt.Errorf("failed to update maintenance window: %w", err)
+	}
+	return nil
+}
+
+func (s *MaintenanceService) DeleteWindow(id, operator string) error {
+	var w model.MaintenanceWindow
+	if err := s.db.First(&w, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to find maintenance window: %w", err)
+	}
+	if w.CreatedBy != operator {
+		return fmt.Errorf("no permission")
+	}
+
+	if err := s.db.Delete(&w).Error; err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+	return nil
+}
+
+func (s *MaintenanceService) Initialize(db *infra.Database) error {
+	if err := db.AutoMigrate(&model.MaintenanceWindow{}); err != nil {
+		return err
+	}
+	s.db = db
+	return nil
+}
+
+// inMaintenance 判断服务在 t 时刻是否处于其创建者配置的任一维护窗口内
+func inMaintenance(db *infra.Database, service *model.Service, t time.Time) (bool, error) {
+	var windows []*model.MaintenanceWindow
+	if err := db.Where(&model.MaintenanceWindow{CreatedBy: service.CreatedBy}).Find(&windows).Error; err != nil {
+		return false, err
+	}
+
+	for _, w := range windows {
+		if !w.Covers(service) {
+			continue
+		}
+		if w.StartAt != nil && !t.Before(*w.StartAt) && t.Before(*w.EndAt) {
+			return true, nil
+		}
+		if len(w.Windows) == 0 {
+			continue
+		}
+		if loc, err := loadLocation(w.TimeZone); err == nil && inWindows(w.Windows, t.In(loc)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
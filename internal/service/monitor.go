@@ -15,15 +15,20 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 
 	"github.com/toodofun/pulse/internal/checker"
+	"github.com/toodofun/pulse/internal/cluster"
+	"github.com/toodofun/pulse/internal/config"
 	"github.com/toodofun/pulse/internal/infra"
 	"github.com/toodofun/pulse/internal/model"
 )
@@ -38,24 +43,58 @@ const (
 	ColorBlack   = "var(--color-gray-400)"   // 无数据
 )
 
+type cronJob struct {
+	id      cron.EntryID
+	cancel  context.CancelFunc
+	service *model.Service
+	// 任务加入调度的时间
+	addedAt time.Time
+}
+
 type MonitorService struct {
-	cron   *cron.Cron
-	jobMap sync.Map
+	cfg        config.Scheduler
+	clusterCfg config.Cluster
+	cron       *cron.Cron
+	jobMap     sync.Map
+	scheduleMu sync.Mutex
+	dispatcher *Dispatcher
+	// 检查产生的告警交给 notifications 发送
+	notifications *NotificationService
+
+	// 多副本部署时由 leader 调度全部检查，或由各实例按哈希环分摊
+	instance    string
+	elector     *cluster.Elector
+	leaderStop  context.CancelFunc
+	membership  *cluster.Membership
+	ring        atomic.Pointer[cluster.Ring]
+	clusterDone chan struct{}
+
+	// 所有检查任务的根 context，Stop 时取消
+	ctx    context.Context
+	cancel context.CancelFunc
 
 	db *infra.Database
 }
 
-func NewMonitorService() *MonitorService {
+func NewMonitorService(notifications *NotificationService) *MonitorService {
 	cronClient := cron.New(cron.WithSeconds())
 	cronClient.Start()
 
+	cfg := config.Current().Scheduler
+	ctx, cancel := context.WithCancel(context.Background())
 	return &MonitorService{
-		cron: cronClient,
+		cfg:           cfg,
+		clusterCfg:    config.Current().Cluster,
+		cron:          cronClient,
+		dispatcher:    NewDispatcher(cfg),
+		notifications: notifications,
+		ctx:           ctx,
+		cancel:        cancel,
 	}
 }
 
 func (s *MonitorService) checkService(service *model.Service) error {
-	if service.Interval <= 0 {
+	if service.Cron == "" && service.Interval <= 0 {
 		return fmt.Errorf("service interval must be greater than 0")
 	}
 
@@ -71,6 +110,44 @@ func (s *MonitorService) checkService(service *model.Service) error {
 		return fmt.Errorf("service createdBy cannot be empty")
 	}
 
+	if service.Retries < 0 || service.RetryInterval < 0 || service.FailureThreshold < 0 {
+		return fmt.Errorf("service retries, retryInterval and failureThreshold cannot be negative")
+	}
+
+	if service.Cron == "" && service.Retries*service.RetryInterval >= service.Interval {
+		return fmt.Errorf("service retries * retryInterval must be less than interval")
+	}
+
+	for _, tag := range service.Tags {
+		if tag == "" {
+			return fmt.Errorf("service tags cannot be empty")
+		}
+	}
+
+	if err := checkWindows(service); err != nil {
+		return err
+	}
+
+	if err := checkLocations(service); err != nil {
+		return err
+	}
+
+	if err := checkLocationPolicy(service); err != nil {
+		return err
+	}
+
+	if err := s.checkParents(service); err != nil {
+		return err
+	}
+
+	if err := s.checkChannels(service); err != nil {
+		return err
+	}
+
+	if _, err := parseSchedule(service, s.cfg.CronJitter); err != nil {
+		return fmt.Errorf("invalid service schedule: %w", err)
+	}
+
 	c, err := checker.GetChecker(service.Type)
 	if err != nil {
 		return fmt.Errorf("invalid check type: %w", err)
@@ -107,7 +184,20 @@ func (s *MonitorService) fitRecords(service *model.Service) *model.Service {
 
 	service.Records = records
 	if len(records) > 0 {
-		service.IsSuccess = records[0].IsSuccess
+		// 被抑制的失败不显示为 down
+		service.IsSuccess = records[0].IsSuccess || records[0].SuppressReason != ""
+		service.SuppressReason = records[0].SuppressReason
+	}
+
+	// 已确认的状态优先于最近一条记录，避免单次失败就显示为 down
+	var state model.ServiceState
+	if err := s.db.Limit(1).Find(&state, "service_id = ?", service.ID).Error; err != nil {
+		logrus.Errorf("failed to get service state: %v", err)
+	} else {
+		if state.Status != "" {
+			service.IsSuccess = state.Status == model.StatusUp
+		}
+		service.Flapping = state.Flapping
 	}
 
 	return service
@@ -121,9 +211,64 @@ func (s *MonitorService) GetServiceByID(id, operator string) (*model.Service, er
 	if service.CreatedBy != operator {
 		return nil, fmt.Errorf("no permission")
 	}
+
+	if err := s.db.Where("service_id = ? AND location IN ?", service.ID, service.ProbeLocations()).
+		Order("location").
+		Find(&service.LocationStates).Error; err != nil {
+		logrus.Errorf("failed to list location states: %v", err)
+	}
 	return s.fitRecords(&service), nil
 }
 
+// CheckNow 立即检查一次服务并保存结果，不受调度和活跃时间窗口限制，ctx 被取消时放弃本次检查。
+// 已停用或只由远程探测点检查的服务无法在中心服务上检查
+func (s *MonitorService) CheckNow(ctx context.Context, id, operator string) (*model.Record, error) {
+	var service model.Service
+	if err := s.db.First(&service, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("failed to find service")
+	}
+	if service.CreatedBy != operator {
+		return nil, fmt.Errorf("no permission")
+	}
+	if !service.Enabled {
+		return nil, fmt.Errorf("service %s is disabled", service.Title)
+	}
+	if !service.CheckedLocally() {
+		return nil, fmt.Errorf("service %s is only checked from locations %v", service.Title, service.Locations)
+	}
+
+	r := s.newCheckTask(ctx, &service).execute()
+	if r == nil {
+		return nil, fmt.Errorf("check of service %s was cancelled", service.Title)
+	}
+	return r, nil
+}
+
+// TestResult 为试运行的结果，Error 为服务配置的校验错误
+type TestResult struct {
+	Error  string        `json:"error,omitempty"`
+	Record *model.Record `json:"record,omitempty"`
+}
+
+// TestService 校验服务配置并执行一次检查，不保存任何数据，ctx 被取消时检查尽快返回
+func (s *MonitorService) TestService(ctx context.Context, service *model.Service, operator string) *TestResult {
+	service.ID = ""
+	service.CreatedBy = operator
+
+	res := &TestResult{}
+	if err := s.checkService(service); err != nil {
+		res.Error = err.Error()
+	}
+
+	// 配置的其他部分有误时仍然执行检查，便于同时调试检查参数
+	c, err := checker.GetChecker(service.Type)
+	if err != nil {
+		return res
+	}
+	res.Record = c.Check(ctx, service.Fields)
+	return res
+}
+
 func (s *MonitorService) UpdateService(service *model.Service, id, operator string) error {
 	var res model.Service
 	if err := s.db.First(&res, "id = ?", id).Error; err != nil {
@@ -134,12 +279,12 @@ func (s *MonitorService) UpdateService(service *model.Service, id, operator stri
 		return fmt.Errorf("no permission")
 	}
 
+	service.ID = res.ID
+	service.CreatedBy = operator
 	if err := s.checkService(service); err != nil {
 		return err
 	}
 
-	service.CreatedBy = operator
-	service.ID = res.ID
 	service.CreatedAt = res.CreatedAt
 	service.Enabled = res.Enabled
 
@@ -148,25 +293,27 @@ func (s *MonitorService) UpdateService(service *model.Service, id, operator stri
 		return fmt.Errorf("failed to update service")
 	}
 
+	// 替换正在运行的调度任务，使新的配置立即生效
+	if err := s.reschedule(service); err != nil {
+		return fmt.Errorf("failed to reschedule service %s: %w", service.Title, err)
+	}
+
 	return nil
 }
 
 func (s *MonitorService) AddService(service *model.Service, operator string) error {
+	service.ID = ""
 	service.CreatedBy = operator
 	if err := s.checkService(service); err != nil {
 		return err
 	}
 
-	service.ID = ""
-
 	if err := s.db.Create(service).Error; err != nil {
 		return fmt.Errorf("failed to add service: %w", err)
 	}
 
-	if service.Enabled {
-		if err := s.addCron(service, s.db); err != nil {
-			return fmt.Errorf("failed to add cron job for service %s: %w", service.Title, err)
-		}
+	if err := s.reschedule(service); err != nil {
+		return fmt.Errorf("failed to add cron job for service %s: %w", service.Title, err)
 	}
 
 	return nil
@@ -183,13 +330,28 @@ func (s *MonitorService) DeleteService(serviceID string, operator string) error
 
 	s.delCron(&service)
 
-	if err := s.db.Delete(&service).Error; err != nil {
+	var alert *model.Alert
+	if err := s.db.Transaction(func(tx *gorm.DB) (err error) {
+		if alert, err = closeIncident(tx, &service, "service deleted", operator); err != nil {
+			return err
+		}
+		return tx.Delete(&service).Error
+	}); err != nil {
 		return fmt.Errorf("failed to delete service: %w", err)
 	}
+	s.notify(alert)
 
 	return nil
 }
 
+// deleteStates 删除服务的整体状态和各探测点的状态
+func deleteStates(tx *gorm.DB, serviceID string) error {
+	if err := tx.Delete(&model.LocationState{}, "service_id = ?", serviceID).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&model.ServiceState{}, "service_id = ?", serviceID).Error
+}
+
 func (s *MonitorService) SetEnabled(serviceID string, enabled bool, operator string) error {
 	var service model.Service
 	if err := s.db.First(&service, "id = ?", serviceID).Error; err != nil {
@@ -202,17 +364,24 @@ func (s *MonitorService) SetEnabled(serviceID string, enabled bool, operator str
 
 	service.Enabled = enabled
 
-	if err := s.db.Save(&service).Error; err != nil {
-		return fmt.Errorf("failed to pause service: %w", err)
-	}
-
-	if enabled {
-		return s.addCron(&service, s.db)
-	} else {
+	// 停用时先取消调度，避免执行中的检查在故障解决后重新写入状态
+	if !enabled {
 		s.delCron(&service)
 	}
+	var alert *model.Alert
+	if err := s.db.Transaction(func(tx *gorm.DB) (err error) {
+		if !enabled {
+			if alert, err = closeIncident(tx, &service, "service disabled", operator); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&service).Error
+	}); err != nil {
+		return fmt.Errorf("failed to pause service: %w", err)
+	}
+	s.notify(alert)
 
-	return nil
+	return s.reschedule(&service)
 }
 
 func (s *MonitorService) SetPrivate(serviceID string, private bool, operator string) error {
@@ -258,9 +427,9 @@ func (s *MonitorService) GetDailySuccessRatios(serviceID string, operator string
 		var total int64
 		var success int64
 
-		// 查询当天总记录数
+		// 查询当天总记录数，被抑制的失败不计入
 		if err := s.db.Model(&model.Record{}).
-			Where("service_id = ? AND monitor_at >= ? AND monitor_at < ?", serviceID, dayStart, dayEnd).
+			Where("service_id = ? AND monitor_at >= ? AND monitor_at < ? AND suppress_reason = ?", serviceID, dayStart, dayEnd, "").
 			Count(&total).Error; err != nil {
 			return nil, err
 		}
@@ -305,49 +474,115 @@ func (s *MonitorService) GetDailySuccessRatios(serviceID string, operator string
 	return results, nil
 }
 
-func (s *MonitorService) addCron(service *model.Service, db *infra.Database) error {
-	task := NewCheckTask(service, db)
-	jobID, err := s.cron.AddJob(fmt.Sprintf("@every %ds", service.Interval), task)
-	if err != nil {
-		return fmt.Errorf("failed to add job for service %s: %w", service.Title, err)
+// SchedulerStats 为当前实例的调度状态，多副本部署时每个实例只统计自己负责的服务
+type SchedulerStats struct {
+	*DispatcherStats
+	Instance string   `json:"instance"`
+	Mode     string   `json:"mode"`
+	Leader   bool     `json:"leader"`
+	Members  []string `json:"members,omitempty"`
+	Jobs     int      `json:"jobs"`
+}
+
+func (s *MonitorService) GetSchedulerStats() *SchedulerStats {
+	stats := &SchedulerStats{
+		DispatcherStats: s.dispatcher.Stats(),
+		Instance:        s.instance,
+		Mode:            s.clusterCfg.Mode,
+		Leader:          s.elector != nil && s.elector.IsLeader(),
+	}
+	if ring := s.ring.Load(); ring != nil {
+		stats.Members = ring.Members()
+	}
+	s.jobMap.Range(func(_, _ any) bool {
+		stats.Jobs++
+		return true
+	})
+	return stats
+}
+
+// notify 发送服务操作产生的告警，alert 为空时忽略
+func (s *MonitorService) notify(alert *model.Alert) {
+	if alert != nil && s.notifications != nil {
+		s.notifications.Notify(alert)
 	}
-	s.jobMap.Store(service.ID, jobID)
+}
+
+// newCheckTask 创建检查任务，检查产生的告警发送到服务绑定的通知渠道
+func (s *MonitorService) newCheckTask(ctx context.Context, service *model.Service) *CheckTask {
+	task := NewCheckTask(ctx, service, s.db)
+	if s.notifications != nil {
+		task.notify = s.notifications.Notify
+	}
+	return task
+}
+
+// addCron 为服务添加调度任务，immediate 为 true 时立即执行一次检查。
+// 启动时不立即执行，由带相位的调度在一个周期内分散触发首次检查。
+func (s *MonitorService) addCron(service *model.Service, immediate bool) error {
+	schedule, err := parseSchedule(service, s.cfg.CronJitter)
+	if err != nil {
+		return fmt.Errorf("failed to parse schedule for service %s: %w", service.Title, err)
+	}
+	// 复制一份服务配置，避免调用方后续修改影响已调度的任务
+	snapshot := *service
+	ctx, cancel := context.WithCancel(s.ctx)
+	task := s.newCheckTask(ctx, &snapshot)
+
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+	s.removeJob(service.ID)
+	jobID := s.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.dispatcher.Submit(task)
+	}))
+	s.jobMap.Store(service.ID, &cronJob{id: jobID, cancel: cancel, service: &snapshot, addedAt: time.Now()})
 	logrus.Infof("Job for service %s added with ID %d", service.Title, jobID)
 
-	// 立即执行一次任务
-	go task.Run()
+	if immediate {
+		s.dispatcher.Submit(task)
+	}
 
 	return nil
 }
 
+// delCron 移除服务的调度任务，并取消正在排队或执行中的检查
 func (s *MonitorService) delCron(service *model.Service) {
-	if jobID, ok := s.jobMap.Load(service.ID); ok {
-		s.cron.Remove(jobID.(cron.EntryID))
-		s.jobMap.Delete(service.ID)
-		logrus.Infof("Job for service %s removed", service.Title)
-	}
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+	s.removeJob(service.ID)
 }
 
-func (s *MonitorService) start(db *infra.Database) error {
-	services := make([]*model.Service, 0)
-	if err := db.Where(&model.Service{Enabled: true}).Find(&services, &model.Service{Enabled: true}).Error; err != nil {
-		return err
+// removeJob 需要在持有 scheduleMu 时调用
+func (s *MonitorService) removeJob(serviceID string) {
+	if job, ok := s.jobMap.LoadAndDelete(serviceID); ok {
+		s.cron.Remove(job.(*cronJob).id)
+		job.(*cronJob).cancel()
+		logrus.Infof("Job for service %s removed", job.(*cronJob).service.Title)
 	}
-	for _, service := range services {
-		if err := s.addCron(service, db); err != nil {
-			return err
+}
+
+// Stop 停止调度器并取消所有检查，释放 leader 锁或退出集群，等待执行中的检查退出或 ctx 超时
+func (s *MonitorService) Stop(ctx context.Context) error {
+	<-s.cron.Stop().Done()
+	s.cancel()
+	if s.clusterDone != nil {
+		select {
+		case <-s.clusterDone:
+		case <-ctx.Done():
 		}
 	}
-	return nil
+	return s.dispatcher.Wait(ctx)
 }
 
 func (s *MonitorService) Initialize(db *infra.Database) error {
-	if err := db.AutoMigrate(&model.Service{}, &model.Record{}); err != nil {
-		return err
-	}
-	if err := s.start(db); err != nil {
+	if err := db.AutoMigrate(&model.Service{}, &model.Record{}, &model.ServiceState{}, &model.LocationState{}); err != nil {
 		return err
 	}
 	s.db = db
 	return nil
 }
+
+// Start 开始调度检查，检查会访问其他 Service 的表，须在所有 Service 初始化后调用
+func (s *MonitorService) Start() error {
+	return s.startCluster()
+}
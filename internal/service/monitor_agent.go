@@ -0,0 +1,99 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/toodofun/pulse/internal/config"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+// checkLocations 校验服务的探测点，只允许本机和配置了 token 的探测点
+func checkLocations(service *model.Service) error {
+	seen := make(map[string]struct{}, len(service.Locations))
+	for _, location := range service.Locations {
+		if _, ok := seen[location]; ok {
+			return fmt.Errorf("duplicate location %q", location)
+		}
+		seen[location] = struct{}{}
+
+		if location == model.LocationLocal {
+			continue
+		}
+		if _, ok := config.Current().Server.AgentTokens[location]; !ok {
+			return fmt.Errorf("unknown location %q", location)
+		}
+	}
+	return nil
+}
+
+// ListAgentServices 返回分配给探测点的所有已启用服务
+func (s *MonitorService) ListAgentServices(location string) ([]*model.Service, error) {
+	var services []*model.Service
+	if err := s.db.Where(&model.Service{Enabled: true}).
+		Where("locations LIKE ?", fmt.Sprintf("%%%q%%", location)).
+		Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	res := make([]*model.Service, 0, len(services))
+	for _, service := range services {
+		if service.HasLocation(location) {
+			res = append(res, service)
+		}
+	}
+	return res, nil
+}
+
+// SaveAgentRecords 保存探测点上报的检查结果，忽略未分配给该探测点的服务，返回保存的数量
+func (s *MonitorService) SaveAgentRecords(location string, records []*model.Record) (int, error) {
+	ids := make([]string, 0, len(records))
+	for _, r := range records {
+		ids = append(ids, r.ServiceID)
+	}
+
+	var services []*model.Service
+	if err := s.db.Where("id IN ?", ids).Find(&services).Error; err != nil {
+		return 0, fmt.Errorf("failed to find services: %w", err)
+	}
+	serviceMap := make(map[string]*model.Service, len(services))
+	for _, service := range services {
+		serviceMap[service.ID] = service
+	}
+
+	// 按检查时间顺序保存，保证状态按顺序变化
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].MonitorAt.Before(records[j].MonitorAt)
+	})
+
+	saved := 0
+	for _, r := range records {
+		service, ok := serviceMap[r.ServiceID]
+		if !ok || !service.Enabled || !service.HasLocation(location) || r.MonitorAt.IsZero() {
+			logrus.Warnf("ignore record of service %s from location %s", r.ServiceID, location)
+			continue
+		}
+		r.ID = 0
+		r.Location = location
+		r.SuppressReason = ""
+		s.newCheckTask(s.ctx, service).save(r)
+		saved++
+	}
+	return saved, nil
+}
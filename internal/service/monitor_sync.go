@@ -0,0 +1,267 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/toodofun/pulse/internal/cluster"
+	"github.com/toodofun/pulse/internal/config"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const schedulerLockName = "scheduler"
+
+func (s *MonitorService) startCluster() error {
+	s.instance = instanceID()
+	switch s.clusterCfg.Mode {
+	case config.ClusterModeShard:
+		return s.startSharding()
+	case config.ClusterModeLeader, "":
+		return s.startElection()
+	default:
+		return fmt.Errorf("unsupported cluster mode: %s", s.clusterCfg.Mode)
+	}
+}
+
+// startElection 竞选 leader，只有 leader 调度检查，其余副本只提供 API
+func (s *MonitorService) startElection() error {
+	lock, err := cluster.NewLock(s.db, schedulerLockName, s.instance, s.clusterCfg.LeaseDuration)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduler lock: %w", err)
+	}
+
+	s.elector = cluster.NewElector(lock, s.clusterCfg.LeaseDuration, s.clusterCfg.RenewInterval)
+	s.elector.OnStartedLeading = s.onStartedLeading
+	s.elector.OnStoppedLeading = s.onStoppedLeading
+
+	s.clusterDone = make(chan struct{})
+	go func() {
+		defer close(s.clusterDone)
+		s.elector.Run(s.ctx)
+	}()
+	return nil
+}
+
+// startSharding 加入集群，所有实例按服务 ID 的一致性哈希分摊检查
+func (s *MonitorService) startSharding() error {
+	membership, err := cluster.NewMembership(s.db, s.instance, s.clusterCfg.LeaseDuration)
+	if err != nil {
+		return fmt.Errorf("failed to join cluster: %w", err)
+	}
+	s.membership = membership
+
+	s.clusterDone = make(chan struct{})
+	go func() {
+		defer close(s.clusterDone)
+		s.shardLoop(s.ctx)
+	}()
+	return nil
+}
+
+func (s *MonitorService) onStartedLeading() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	s.leaderStop = func() {
+		cancel()
+		<-done
+	}
+	go func() {
+		defer close(done)
+		s.syncLoop(ctx)
+	}()
+}
+
+func (s *MonitorService) onStoppedLeading() {
+	// 等待同步循环退出，避免其在清理后重新添加任务
+	if s.leaderStop != nil {
+		s.leaderStop()
+		s.leaderStop = nil
+	}
+
+	s.removeAll()
+}
+
+func (s *MonitorService) removeAll() {
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+	s.jobMap.Range(func(key, _ any) bool {
+		s.removeJob(key.(string))
+		return true
+	})
+}
+
+// shardLoop 定期发送心跳并刷新哈希环，成员变化时重新分配服务。
+// 重新分配时不立即执行检查：间隔调度的相位只与服务有关，接管的实例会在原来的时间点继续检查。
+func (s *MonitorService) shardLoop(ctx context.Context) {
+	heartbeat := time.NewTicker(s.clusterCfg.RenewInterval)
+	defer heartbeat.Stop()
+	resync := time.NewTicker(s.clusterCfg.SyncInterval)
+	defer resync.Stop()
+
+	var lastHeartbeat time.Time
+	s.refreshRing(ctx, &lastHeartbeat)
+	s.rebalance(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			s.ring.Store(nil)
+			s.removeAll()
+			leaveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := s.membership.Leave(leaveCtx); err != nil {
+				logrus.Warnf("failed to leave cluster: %v", err)
+			}
+			cancel()
+			return
+		case <-heartbeat.C:
+			if s.refreshRing(ctx, &lastHeartbeat) {
+				s.rebalance(ctx)
+			}
+		case <-resync.C:
+			if err := s.reconcile(ctx, true); err != nil {
+				logrus.Errorf("failed to sync services: %v", err)
+			}
+		}
+	}
+}
+
+// refreshRing 发送心跳并刷新哈希环，成员变化时返回 true。
+// 心跳持续失败超过租约时长时清空哈希环，此时其他实例已将本实例视为离开并接管其服务。
+func (s *MonitorService) refreshRing(ctx context.Context, lastHeartbeat *time.Time) bool {
+	err := s.membership.Heartbeat(ctx)
+	var members []string
+	if err == nil {
+		members, err = s.membership.Members(ctx)
+	}
+	if err != nil {
+		logrus.Warnf("failed to refresh cluster members: %v", err)
+		if time.Since(*lastHeartbeat) < s.clusterCfg.LeaseDuration {
+			return false
+		}
+		members = nil
+	} else {
+		*lastHeartbeat = time.Now()
+	}
+
+	ring := cluster.NewRing(members)
+	if ring.Equal(s.ring.Load()) {
+		return false
+	}
+	s.ring.Store(ring)
+	logrus.Infof("cluster members changed: %v", members)
+	return true
+}
+
+func (s *MonitorService) rebalance(ctx context.Context) {
+	if ring := s.ring.Load(); ring == nil || len(ring.Members()) == 0 {
+		s.removeAll()
+		return
+	}
+	if err := s.reconcile(ctx, false); err != nil {
+		logrus.Errorf("failed to rebalance services: %v", err)
+	}
+}
+
+// owns 判断当前实例是否负责调度该服务
+func (s *MonitorService) owns(serviceID string) bool {
+	if s.membership != nil {
+		ring := s.ring.Load()
+		return ring != nil && ring.Owner(serviceID) == s.instance
+	}
+	return s.elector != nil && s.elector.IsLeader()
+}
+
+// syncLoop 周期性地将调度任务与数据库对齐，以感知通过其他副本 API 做出的修改。
+// 首次同步不立即执行检查，由带相位的调度分散触发；之后新增或修改的服务立即检查一次。
+func (s *MonitorService) syncLoop(ctx context.Context) {
+	if err := s.reconcile(ctx, false); err != nil {
+		logrus.Errorf("failed to schedule services: %v", err)
+	}
+
+	ticker := time.NewTicker(s.clusterCfg.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.reconcile(ctx, true); err != nil {
+				logrus.Errorf("failed to sync services: %v", err)
+			}
+		}
+	}
+}
+
+func (s *MonitorService) reconcile(ctx context.Context, immediate bool) error {
+	start := time.Now()
+	services := make([]*model.Service, 0)
+	if err := s.db.WithContext(ctx).Where(&model.Service{Enabled: true}).Find(&services).Error; err != nil {
+		return err
+	}
+
+	owned := make(map[string]struct{}, len(services))
+	for _, service := range services {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !service.CheckedLocally() || !s.owns(service.ID) {
+			continue
+		}
+		owned[service.ID] = struct{}{}
+		// 数据库中的时间精度可能低于内存中的值，按秒比较避免重复调度
+		if job, ok := s.jobMap.Load(service.ID); ok &&
+			job.(*cronJob).service.UpdatedAt.Truncate(time.Second).Equal(service.UpdatedAt.Truncate(time.Second)) {
+			continue
+		}
+		if err := s.addCron(service, immediate); err != nil {
+			logrus.Errorf("failed to schedule service %s: %v", service.Title, err)
+		}
+	}
+
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+	s.jobMap.Range(func(key, job any) bool {
+		// 查询之后通过 API 加入的任务不在查询结果中，留给下一次同步判断
+		if _, ok := owned[key.(string)]; !ok && job.(*cronJob).addedAt.Before(start) {
+			s.removeJob(key.(string))
+		}
+		return true
+	})
+	return nil
+}
+
+// reschedule 在服务通过 API 修改后更新调度任务。
+// 服务不归当前实例调度时，由负责的实例在下一次同步时生效。
+func (s *MonitorService) reschedule(service *model.Service) error {
+	if !service.Enabled || !service.CheckedLocally() || !s.owns(service.ID) {
+		s.delCron(service)
+		return nil
+	}
+	return s.addCron(service, true)
+}
+
+func instanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "pulse"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+}
@@ -0,0 +1,365 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/toodofun/pulse/internal/config"
+	"github.com/toodofun/pulse/internal/infra"
+	"github.com/toodofun/pulse/internal/model"
+	"github.com/toodofun/pulse/internal/notifier"
+)
+
+var alertTypes = []string{
+	model.AlertIncidentOpened,
+	model.AlertIncidentResolved,
+	model.AlertFlapping,
+	model.AlertStable,
+}
+
+// NotificationService 管理通知渠道，并在后台将告警发送到服务绑定的渠道
+type NotificationService struct {
+	cfg   config.Notifier
+	queue chan *notification
+	// 排队和发送中的通知数量
+	pending atomic.Int64
+	wg      sync.WaitGroup
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	db *infra.Database
+}
+
+type notification struct {
+	channel *model.NotificationChannel
+	alert   *model.Alert
+}
+
+func NewNotificationService() *NotificationService {
+	cfg := config.Current().Notifier
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &NotificationService{
+		cfg:    cfg,
+		queue:  make(chan *notification, cfg.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (s *NotificationService) checkChannel(c *model.NotificationChannel) error {
+	if c.Title == "" {
+		return errors.New("channel title cannot be empty")
+	}
+
+	for _, alertType := range c.Alerts {
+		if !slices.Contains(alertTypes, alertType) {
+			return fmt.Errorf("unknown alert type: %s", alertType)
+		}
+	}
+
+	n, err := notifier.GetNotifier(c.Type)
+	if err != nil {
+		return fmt.Errorf("invalid channel type: %w", err)
+	}
+	return n.Validate(c.Config)
+}
+
+func (s *NotificationService) ListChannels(operator string) ([]*model.NotificationChannel, error) {
+	var channels []*model.NotificationChannel
+	if err := s.db.Where(&model.NotificationChannel{CreatedBy: operator}).
+		Order("created_at DESC").
+		Find(&channels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list channels: %w", err)
+	}
+	return channels, nil
+}
+
+func (s *NotificationService) findChannel(id, operator string) (*model.NotificationChannel, error) {
+	var channel model.NotificationChannel
+	if err := s.db.First(&channel, "id = ?", id).Error; err != nil {
+		return nil, fmt.Errorf("failed to find channel: %w", err)
+	}
+	if channel.CreatedBy != operator {
+		return nil, fmt.Errorf("no permission")
+	}
+	return &channel, nil
+}
+
+// AddChannel 新增渠道，新建的渠道总是启用，通过 SetChannelEnabled 停用
+func (s *NotificationService) AddChannel(c *model.NotificationChannel, operator string) error {
+	c.ID = ""
+	c.CreatedBy = operator
+	c.Enabled = true
+	if err := s.checkChannel(c); err != nil {
+		return err
+	}
+
+	if err := s.db.Create(c).Error; err != nil {
+		return fmt.Errorf("failed to add channel: %w", err)
+	}
+	return nil
+}
+
+func (s *NotificationService) UpdateChannel(c *model.NotificationChannel, id, operator string) error {
+	res, err := s.findChannel(id, operator)
+	if err != nil {
+		return err
+	}
+
+	c.ID = res.ID
+	c.CreatedBy = res.CreatedBy
+	c.CreatedAt = res.CreatedAt
+	c.Enabled = res.Enabled
+	if err = s.checkChannel(c); err != nil {
+		return err
+	}
+
+	if err = s.db.Save(c).Error; err != nil {
+		return fmt.Errorf("failed to update channel: %w", err)
+	}
+	return nil
+}
+
+func (s *NotificationService) SetChannelEnabled(id string, enabled bool, operator string) error {
+	channel, err := s.findChannel(id, operator)
+	if err != nil {
+		return err
+	}
+
+	channel.Enabled = enabled
+	if err = s.db.Save(channel).Error; err != nil {
+		return fmt.Errorf("failed to update channel: %w", err)
+	}
+	return nil
+}
+
+// DeleteChannel 删除渠道，服务上残留的绑定在发送时被忽略
+func (s *NotificationService) DeleteChannel(id, operator string) error {
+	channel, err := s.findChannel(id, operator)
+	if err != nil {
+		return err
+	}
+
+	if err = s.db.Delete(channel).Error; err != nil {
+		return fmt.Errorf("failed to delete channel: %w", err)
+	}
+	return nil
+}
+
+// TestChannel 同步发送一条测试通知，返回发送结果
+func (s *NotificationService) TestChannel(id, operator string) (*model.NotificationDelivery, error) {
+	channel, err := s.findChannel(id, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	message := "this is a test notification from Pulse"
+	alert := &model.Alert{
+		Type:    model.AlertTest,
+		Service: &model.Service{Title: "Pulse", CreatedBy: operator},
+		Incident: &model.Incident{
+			Status:    model.IncidentOpen,
+			StartedAt: now,
+			Message:   message,
+		},
+		Record: &model.Record{Message: message, MonitorAt: now},
+		At:     now,
+	}
+	return s.deliver(s.ctx, channel, alert), nil
+}
+
+// ListDeliveries 返回最近的发送记录，参数为空时不按该条件过滤
+func (s *NotificationService) ListDeliveries(operator, channelID, serviceID string,
+	incidentID uint64, limit int) ([]*model.NotificationDelivery, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	owned := s.db.Unscoped().Model(&model.NotificationChannel{}).Select("id").Where("created_by = ?", operator)
+	query := s.db.Where("channel_id IN (?)", owned)
+	if channelID != "" {
+		query = query.Where("channel_id = ?", channelID)
+	}
+	if serviceID != "" {
+		query = query.Where("service_id = ?", serviceID)
+	}
+	if incidentID != 0 {
+		query = query.Where("incident_id = ?", incidentID)
+	}
+
+	deliveries := make([]*model.NotificationDelivery, 0)
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// Notify 将告警放入发送队列，不会阻塞调用方，队列已满时丢弃
+func (s *NotificationService) Notify(alert *model.Alert) {
+	if len(alert.Service.Channels) == 0 {
+		return
+	}
+
+	var channels []*model.NotificationChannel
+	if err := s.db.Where("id IN ? AND created_by = ? AND enabled = ?",
+		alert.Service.Channels, alert.Service.CreatedBy, true).
+		Find(&channels).Error; err != nil {
+		logrus.Errorf("failed to find channels of service %s: %v", alert.Service.Title, err)
+		return
+	}
+
+	for _, channel := range channels {
+		if !channel.Accepts(alert.Type) {
+			continue
+		}
+		s.pending.Add(1)
+		select {
+		case s.queue <- &notification{channel: channel, alert: alert}:
+		default:
+			s.pending.Add(-1)
+			logrus.Warnf("notification queue is full, drop %s of service %s to channel %s",
+				alert.Type, alert.Service.Title, channel.Title)
+		}
+	}
+}
+
+func (s *NotificationService) work() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case n := <-s.queue:
+			s.deliver(s.ctx, n.channel, n.alert)
+			s.pending.Add(-1)
+		}
+	}
+}
+
+// deliver 通过渠道发送告警并保存发送记录，与故障相关的通知同时记录在故障时间线上
+func (s *NotificationService) deliver(ctx context.Context, channel *model.NotificationChannel,
+	alert *model.Alert) *model.NotificationDelivery {
+	delivery := &model.NotificationDelivery{
+		ChannelID: channel.ID,
+		ServiceID: alert.Service.ID,
+		AlertType: alert.Type,
+		Status:    model.DeliverySuccess,
+	}
+	if alert.Incident != nil {
+		delivery.IncidentID = alert.Incident.ID
+	}
+
+	start := time.Now()
+	err := s.send(ctx, channel, alert)
+	delivery.Duration = time.Since(start).Milliseconds()
+	message := fmt.Sprintf("sent %s to %s", alert.Type, channel.Title)
+	if err != nil {
+		delivery.Status = model.DeliveryFailed
+		delivery.Error = err.Error()
+		message = fmt.Sprintf("failed to send %s to %s: %v", alert.Type, channel.Title, err)
+		logrus.Warnf("failed to send %s of service %s to channel %s: %v",
+			alert.Type, alert.Service.Title, channel.Title, err)
+	}
+
+	if err = s.db.Create(delivery).Error; err != nil {
+		logrus.Errorf("failed to save delivery to channel %s: %v", channel.Title, err)
+	}
+	if delivery.IncidentID != 0 {
+		if err = addIncidentEvent(s.db.DB, delivery.IncidentID, model.IncidentEventNotification,
+			message, "", time.Now()); err != nil {
+			logrus.Errorf("failed to add notification to incident %d: %v", delivery.IncidentID, err)
+		}
+	}
+	return delivery
+}
+
+func (s *NotificationService) send(ctx context.Context, channel *model.NotificationChannel, alert *model.Alert) error {
+	n, err := notifier.GetNotifier(channel.Type)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	return n.Notify(ctx, channel.Config, alert)
+}
+
+// Stop 等待队列中的通知发送完成，ctx 超时后取消未完成的发送
+func (s *NotificationService) Stop(ctx context.Context) error {
+	defer s.wg.Wait()
+	defer s.cancel()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for s.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %d notifications: %w", s.pending.Load(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func (s *NotificationService) Initialize(db *infra.Database) error {
+	if err := db.AutoMigrate(&model.NotificationChannel{}, &model.NotificationDelivery{}); err != nil {
+		return err
+	}
+	s.db = db
+
+	for i := 0; i < s.cfg.Workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+	return nil
+}
+
+// checkChannels 校验服务绑定的通知渠道属于同一用户
+func (s *MonitorService) checkChannels(service *model.Service) error {
+	if len(service.Channels) == 0 {
+		return nil
+	}
+
+	for i, id := range service.Channels {
+		if slices.Contains(service.Channels[:i], id) {
+			return fmt.Errorf("duplicate channel %s", id)
+		}
+	}
+
+	var count int64
+	if err := s.db.Model(&model.NotificationChannel{}).
+		Where("id IN ? AND created_by = ?", service.Channels, service.CreatedBy).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to find channels: %w", err)
+	}
+	if int(count) != len(service.Channels) {
+		return errors.New("some channels do not exist")
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+func TestNotificationService_SetChannelEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		// 依次执行的操作，update 时提交与当前相反的 enabled
+		ops  []string
+		want bool
+	}{
+		{name: "created enabled", want: true},
+		{name: "update keeps enabled", ops: []string{"update"}, want: true},
+		{name: "update keeps disabled", ops: []string{"disable", "update"}, want: false},
+		{name: "enable after disable", ops: []string{"disable", "enable"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			s := NewNotificationService()
+			s.db = db
+
+			channel := &model.NotificationChannel{Title: tt.name, Type: "log", Enabled: false}
+			if err := s.AddChannel(channel, "alice"); err != nil {
+				t.Fatalf("AddChannel() error = %v", err)
+			}
+			for _, op := range tt.ops {
+				var err error
+				switch op {
+				case "enable", "disable":
+					err = s.SetChannelEnabled(channel.ID, op == "enable", "alice")
+				case "update":
+					current, _ := s.findChannel(channel.ID, "alice")
+					err = s.UpdateChannel(&model.NotificationChannel{Title: tt.name, Type: "log",
+						Enabled: !current.Enabled}, channel.ID, "alice")
+				}
+				if err != nil {
+					t.Fatalf("%s error = %v", op, err)
+				}
+			}
+
+			got, err := s.findChannel(channel.ID, "alice")
+			if err != nil {
+				t.Fatalf("findChannel() error = %v", err)
+			}
+			if got.Enabled != tt.want {
+				t.Errorf("enabled = %v, want %v", got.Enabled, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationService_Notify(t *testing.T) {
+	tests := []struct {
+		name      string
+		alertType string
+		want      []string
+	}{
+		{name: "opened", alertType: model.AlertIncidentOpened, want: []string{"all"}},
+		{name: "resolved", alertType: model.AlertIncidentResolved, want: []string{"all", "resolved only"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			s := NewNotificationService()
+			s.db = db
+
+			channels := []struct {
+				title    string
+				owner    string
+				disabled bool
+				alerts   []string
+			}{
+				{title: "all", owner: "alice"},
+				{title: "resolved only", owner: "alice", alerts: []string{model.AlertIncidentResolved}},
+				{title: "disabled", owner: "alice", disabled: true},
+				{title: "other owner", owner: "bob"},
+			}
+			service := &model.Service{Title: tt.name, CreatedBy: "alice"}
+			for _, c := range channels {
+				channel := &model.NotificationChannel{Title: c.title, Type: "log", Alerts: c.alerts}
+				if err := s.AddChannel(channel, c.owner); err != nil {
+					t.Fatalf("AddChannel() error = %v", err)
+				}
+				if c.disabled {
+					if err := s.SetChannelEnabled(channel.ID, false, c.owner); err != nil {
+						t.Fatalf("SetChannelEnabled() error = %v", err)
+					}
+				}
+				service.Channels = append(service.Channels, channel.ID)
+			}
+
+			s.Notify(&model.Alert{Type: tt.alertType, Service: service, At: time.Now()})
+
+			var got []string
+			for len(s.queue) > 0 {
+				got = append(got, (<-s.queue).channel.Title)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("queued = %v, want %v", got, tt.want)
+			}
+			if s.pending.Load() != int64(len(tt.want)) {
+				t.Errorf("pending = %d, want %d", s.pending.Load(), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestNotificationService_deliver(t *testing.T) {
+	tests := []struct {
+		name        string
+		channelType model.NotifierType
+		wantStatus  string
+		wantError   string
+	}{
+		{name: "success", channelType: "log", wantStatus: model.DeliverySuccess},
+		{name: "unknown type", channelType: "unknown", wantStatus: model.DeliveryFailed, wantError: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			s := NewNotificationService()
+			s.db = db
+
+			service := &model.Service{ID: "service", Title: tt.name, CreatedBy: "alice"}
+			incident := &model.Incident{ServiceID: service.ID, Status: model.IncidentOpen, StartedAt: time.Now()}
+			if err := db.Create(incident).Error; err != nil {
+				t.Fatalf("failed to create incident: %v", err)
+			}
+			channel := &model.NotificationChannel{Title: tt.name, Type: tt.channelType, Enabled: true, CreatedBy: "alice"}
+			if err := db.Create(channel).Error; err != nil {
+				t.Fatalf("failed to create channel: %v", err)
+			}
+
+			alert := &model.Alert{Type: model.AlertIncidentOpened, Service: service, Incident: incident, At: time.Now()}
+			delivery := s.deliver(s.ctx, channel, alert)
+			if delivery.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", delivery.Status, tt.wantStatus)
+			}
+			if !strings.Contains(delivery.Error, tt.wantError) || (tt.wantError == "") != (delivery.Error == "") {
+				t.Errorf("error = %q, want %q", delivery.Error, tt.wantError)
+			}
+
+			var saved model.NotificationDelivery
+			if err := db.First(&saved, "channel_id = ?", channel.ID).Error; err != nil {
+				t.Fatalf("failed to find delivery: %v", err)
+			}
+			if saved.IncidentID != incident.ID || saved.Status != tt.wantStatus {
+				t.Errorf("saved delivery = %+v", saved)
+			}
+			var events int64
+			if err := db.Model(&model.IncidentEvent{}).
+				Where("incident_id = ? AND type = ?", incident.ID, model.IncidentEventNotification).
+				Count(&events).Error; err != nil {
+				t.Fatalf("failed to count events: %v", err)
+			}
+			if events != 1 {
+				t.Errorf("notification events = %d, want 1", events)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+const windowTimeLayout = "15:04"
+
+// 秒字段可选，同时兼容标准的 5 段表达式和 @every 之类的描述符
+var scheduleParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// parseSchedule 解析服务的调度表达式，并按服务 ID 加上固定的抖动，避免相同周期的服务同时执行。
+// 间隔调度的相位分布在整个周期内，cron 表达式则最多延后 cronJitter。
+func parseSchedule(service *model.Service, cronJitter time.Duration) (cron.Schedule, error) {
+	if service.Cron == "" {
+		if service.Interval <= 0 {
+			return nil, fmt.Errorf("interval must be greater than 0")
+		}
+		interval := time.Duration(service.Interval) * time.Second
+		return &intervalSchedule{
+			interval: interval,
+			offset:   jitterOf(service, interval),
+		}, nil
+	}
+
+	spec := service.Cron
+	if service.TimeZone != "" {
+		spec = fmt.Sprintf("CRON_TZ=%s %s", service.TimeZone, spec)
+	}
+	schedule, err := scheduleParser.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	if delay := jitterOf(service, cronJitter); delay > 0 {
+		return &delaySchedule{schedule: schedule, delay: delay}, nil
+	}
+	return schedule, nil
+}
+
+// jitterOf 根据服务 ID 计算 [0, span) 内的固定偏移，精确到秒
+func jitterOf(service *model.Service, span time.Duration) time.Duration {
+	// span 为负数时不延后
+	if span < time.Second {
+		return 0
+	}
+	seconds := uint64(span / time.Second)
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(service.ID))
+	return time.Duration(h.Sum64()%seconds) * time.Second
+}
+
+// intervalSchedule 以 Unix 纪元为基准对齐周期，每个服务固定落在 offset 相位上。
+// 进程重启后首次执行也会落在该相位，从而把启动时的检查分散到一个周期内。
+type intervalSchedule struct {
+	interval time.Duration
+	offset   time.Duration
+}
+
+func (s *intervalSchedule) Next(t time.Time) time.Time {
+	interval := int64(s.interval / time.Second)
+	offset := int64(s.offset / time.Second)
+	sec := t.Unix()
+	next := sec - ((sec-offset)%interval+interval)%interval + interval
+	return time.Unix(next, 0).In(t.Location())
+}
+
+// delaySchedule 将 cron 表达式的每次触发统一延后 delay
+type delaySchedule struct {
+	schedule cron.Schedule
+	delay    time.Duration
+}
+
+func (s *delaySchedule) Next(t time.Time) time.Time {
+	return s.schedule.Next(t.Add(-s.delay)).Add(s.delay)
+}
+
+func checkWindows(service *model.Service) error {
+	if _, err := loadLocation(service.TimeZone); err != nil {
+		return fmt.Errorf("invalid service timezone: %w", err)
+	}
+	return normalizeWindows(service.Windows)
+}
+
+// normalizeWindows 校验时间窗口，并将时间统一为 HH:MM，便于按字符串比较
+func normalizeWindows(windows []model.ActiveWindow) error {
+	for i, w := range windows {
+		start, err := time.Parse(windowTimeLayout, w.Start)
+		if err != nil {
+			return fmt.Errorf("windows[%d]: start must be in HH:MM format", i)
+		}
+		end, err := time.Parse(windowTimeLayout, w.End)
+		if err != nil {
+			return fmt.Errorf("windows[%d]: end must be in HH:MM format", i)
+		}
+		if start.Equal(end) {
+			return fmt.Errorf("windows[%d]: start and end cannot be equal", i)
+		}
+		windows[i].Start = start.Format(windowTimeLayout)
+		windows[i].End = end.Format(windowTimeLayout)
+		for _, d := range w.Weekdays {
+			if d < time.Sunday || d > time.Saturday {
+				return fmt.Errorf("windows[%d]: weekdays must be between 0 (Sunday) and 6 (Saturday)", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// inActiveWindow 判断 t 是否处于服务的活跃时间窗口内，未配置窗口时始终活跃
+func inActiveWindow(service *model.Service, t time.Time) bool {
+	if len(service.Windows) == 0 {
+		return true
+	}
+
+	loc, err := loadLocation(service.TimeZone)
+	if err != nil {
+		return true
+	}
+	return inWindows(service.Windows, t.In(loc))
+}
+
+// inWindows 判断 t 是否处于任一时间窗口内，t 需已转换到窗口所在的时区
+func inWindows(windows []model.ActiveWindow, t time.Time) bool {
+	now := t.Format(windowTimeLayout)
+
+	for _, w := range windows {
+		switch {
+		case w.Start < w.End:
+			if now >= w.Start && now < w.End && matchWeekday(w, t.Weekday()) {
+				return true
+			}
+		case now >= w.Start:
+			// 跨午夜的窗口，例如 22:00-06:00，星期以窗口开始的那天为准
+			if matchWeekday(w, t.Weekday()) {
+				return true
+			}
+		case now < w.End:
+			if matchWeekday(w, t.AddDate(0, 0, -1).Weekday()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchWeekday(w model.ActiveWindow, d time.Weekday) bool {
+	return len(w.Weekdays) == 0 || slices.Contains(w.Weekdays, d)
+}
+
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
+}
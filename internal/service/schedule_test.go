@@ -0,0 +1,138 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/toodofun/pulse/internal/model"
+)
+
+func TestInActiveWindow(t *testing.T) {
+	service := &model.Service{
+		TimeZone: "Asia/Shanghai",
+		Windows: []model.ActiveWindow{
+			{Weekdays: []time.Weekday{time.Monday, time.Friday}, Start: "9:30", End: "15:00"},
+			{Weekdays: []time.Weekday{time.Saturday}, Start: "22:00", End: "02:00"},
+		},
+	}
+	if err := checkWindows(service); err != nil {
+		t.Fatalf("checkWindows() error = %v", err)
+	}
+
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "monday trading hours", at: time.Date(2025, 6, 2, 10, 0, 0, 0, loc), want: true},
+		{name: "monday before open", at: time.Date(2025, 6, 2, 9, 29, 0, 0, loc), want: false},
+		{name: "monday at close", at: time.Date(2025, 6, 2, 15, 0, 0, 0, loc), want: false},
+		{name: "tuesday", at: time.Date(2025, 6, 3, 10, 0, 0, 0, loc), want: false},
+		{name: "monday in utc", at: time.Date(2025, 6, 2, 2, 0, 0, 0, time.UTC), want: true},
+		{name: "saturday overnight", at: time.Date(2025, 6, 7, 23, 0, 0, 0, loc), want: true},
+		{name: "sunday after midnight", at: time.Date(2025, 6, 8, 1, 0, 0, 0, loc), want: true},
+		{name: "saturday after midnight", at: time.Date(2025, 6, 7, 1, 0, 0, 0, loc), want: false},
+		{name: "saturday noon", at: time.Date(2025, 6, 7, 12, 0, 0, 0, loc), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inActiveWindow(service, tt.at); got != tt.want {
+				t.Errorf("inActiveWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	base := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		service *model.Service
+		jitter  time.Duration
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:    "five fields",
+			service: &model.Service{Cron: "30 10 * * *"},
+			want:    time.Date(2025, 6, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "six fields",
+			service: &model.Service{Cron: "15 */5 * * * *"},
+			want:    time.Date(2025, 6, 2, 10, 0, 15, 0, time.UTC),
+		},
+		{
+			name:    "cron with jitter",
+			service: &model.Service{ID: "a", Cron: "30 10 * * *"},
+			jitter:  time.Minute,
+			want:    time.Date(2025, 6, 2, 10, 30, 0, 0, time.UTC).Add(jitterOf(&model.Service{ID: "a"}, time.Minute)),
+		},
+		{
+			name:    "negative jitter",
+			service: &model.Service{ID: "a", Cron: "30 10 * * *"},
+			jitter:  -time.Second,
+			want:    time.Date(2025, 6, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid",
+			service: &model.Service{Cron: "every day"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSchedule(tt.service, tt.jitter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Next(base).Equal(tt.want) {
+				t.Errorf("parseSchedule().Next() = %v, want %v", got.Next(base), tt.want)
+			}
+		})
+	}
+}
+
+func TestIntervalSchedule(t *testing.T) {
+	base := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	phases := make(map[time.Duration]struct{})
+
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		service := &model.Service{ID: id, Interval: 300}
+		schedule, err := parseSchedule(service, 0)
+		if err != nil {
+			t.Fatalf("parseSchedule() error = %v", err)
+		}
+
+		first := schedule.Next(base)
+		if !first.After(base) || first.Sub(base) > 5*time.Minute {
+			t.Errorf("service %s: first run %v is not within one interval after %v", id, first, base)
+		}
+		if second := schedule.Next(first); second.Sub(first) != 5*time.Minute {
+			t.Errorf("service %s: runs are %v apart, want 5m", id, second.Sub(first))
+		}
+		if again, _ := parseSchedule(service, 0); !again.Next(base).Equal(first) {
+			t.Errorf("service %s: schedule is not deterministic", id)
+		}
+		phases[first.Sub(base)] = struct{}{}
+	}
+
+	if len(phases) < 2 {
+		t.Errorf("services with the same interval share the same phase")
+	}
+}
@@ -15,44 +15,247 @@
 package service
 
 import (
+	"context"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/toodofun/pulse/internal/checker"
+	"github.com/toodofun/pulse/internal/config"
 	"github.com/toodofun/pulse/internal/infra"
 	"github.com/toodofun/pulse/internal/model"
 )
 
 type CheckTask struct {
+	ctx     context.Context
 	service *model.Service
 	db      *infra.Database
+	// report 不为空时检查结果交给 report 上报而不写入数据库，用于 agent 模式
+	report func(r *model.Record)
+	// notify 不为空时用于发送检查产生的告警
+	notify func(alert *model.Alert)
 }
 
-func NewCheckTask(service *model.Service, db *infra.Database) *CheckTask {
+func NewCheckTask(ctx context.Context, service *model.Service, db *infra.Database) *CheckTask {
 	return &CheckTask{
+		ctx:     ctx,
 		service: service,
 		db:      db,
 	}
 }
 
+// NewReportTask 创建将检查结果交给 report 处理的任务
+func NewReportTask(ctx context.Context, service *model.Service, report func(r *model.Record)) *CheckTask {
+	return &CheckTask{
+		ctx:     ctx,
+		service: service,
+		report:  report,
+	}
+}
+
 func (t *CheckTask) Run() {
+	if t.ctx.Err() != nil {
+		return
+	}
+
+	if !inActiveWindow(t.service, time.Now()) {
+		logrus.Debugf("service %s is outside its active windows, skip checking", t.service.Title)
+		return
+	}
+
+	t.execute()
+}
+
+// execute 执行检查并处理结果，返回本次的检查结果，任务被取消时返回 nil
+func (t *CheckTask) execute() *model.Record {
 	logrus.Debugf("checking service start: %s", t.service.Title)
 	c, err := checker.GetChecker(t.service.Type)
 	if err != nil {
 		logrus.Errorf("get checker error: %s", err.Error())
-		t.db.Create(&model.Record{
+		r := &model.Record{
 			ServiceID:    t.service.ID,
 			IsSuccess:    false,
 			ResponseTime: 0,
 			Message:      err.Error(),
 			MonitorAt:    time.Now(),
-		})
-		return
+		}
+		t.finish(r)
+		return r
 	}
 
-	r := c.Check(t.service.Fields)
+	r := t.check(c)
+	// 服务已被停用、删除或更新，丢弃本次结果
+	if t.ctx.Err() != nil {
+		logrus.Debugf("checking service %s cancelled: %v", t.service.Title, t.ctx.Err())
+		return nil
+	}
 	r.ServiceID = t.service.ID
-	t.db.Create(r)
+	t.finish(r)
 	logrus.Debugf("checking service end: %s", t.service.Title)
+	return r
+}
+
+// check 执行检查，失败时按服务配置重试，所有尝试都会记录在 Attempts 中
+func (t *CheckTask) check(c checker.Checker) *model.Record {
+	r := c.Check(t.ctx, t.service.Fields)
+	if r.IsSuccess || t.service.Retries <= 0 {
+		return r
+	}
+
+	attempts := []model.Attempt{newAttempt(r)}
+	for i := 0; i < t.service.Retries && !r.IsSuccess; i++ {
+		select {
+		case <-t.ctx.Done():
+			return r
+		case <-time.After(time.Duration(t.service.RetryInterval) * time.Second):
+		}
+		logrus.Debugf("retry checking service %s, attempt %d", t.service.Title, i+2)
+		r = c.Check(t.ctx, t.service.Fields)
+		attempts = append(attempts, newAttempt(r))
+	}
+	r.Attempts = attempts
+
+	return r
+}
+
+func (t *CheckTask) finish(r *model.Record) {
+	if t.report != nil {
+		t.report(r)
+		return
+	}
+	r.Location = model.LocationLocal
+	t.save(r)
+}
+
+func (t *CheckTask) save(r *model.Record) {
+	if !r.IsSuccess {
+		t.suppress(r)
+	}
+	if err := t.db.Create(r).Error; err != nil {
+		logrus.Errorf("failed to save record for service %s: %v", t.service.Title, err)
+		return
+	}
+	alerts, err := t.updateState(r)
+	if err != nil {
+		logrus.Errorf("failed to update state for service %s: %v", t.service.Title, err)
+		return
+	}
+	for _, alert := range alerts {
+		logrus.Infof("alert %s for service %s", alert.Type, t.service.Title)
+		if t.notify != nil {
+			t.notify(alert)
+		}
+	}
+}
+
+// suppress 标记维护窗口内或上游服务 down 时的失败
+func (t *CheckTask) suppress(r *model.Record) {
+	maintenance, err := inMaintenance(t.db, t.service, r.MonitorAt)
+	if err != nil {
+		logrus.Errorf("failed to check maintenance for service %s: %v", t.service.Title, err)
+	} else if maintenance {
+		r.SuppressReason = model.SuppressReasonMaintenance
+		return
+	}
+
+	down, err := dependencyDown(t.db, t.service)
+	if err != nil {
+		logrus.Errorf("failed to check dependencies for service %s: %v", t.service.Title, err)
+	} else if down {
+		r.SuppressReason = model.SuppressReasonDependency
+	}
+}
+
+// updateState 先更新本次检查所在探测点的状态，达到 FailureThreshold 才确认该探测点 down，
+// 再根据未失联探测点的状态决定服务的整体状态，返回需要通知的事件。被抑制的失败不改变状态
+func (t *CheckTask) updateState(r *model.Record) ([]*model.Alert, error) {
+	if r.SuppressReason != "" {
+		return nil, nil
+	}
+
+	var alerts []*model.Alert
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		// 先创建并锁定服务状态行，同一服务的本地检查和 agent 上报在此串行，避免各自读到旧状态后重复打开故障
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.ServiceState{ServiceID: t.service.ID}).Error; err != nil {
+			return err
+		}
+		var state model.ServiceState
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&state, "service_id = ?", t.service.ID).Error; err != nil {
+			return err
+		}
+
+		var ls model.LocationState
+		if err := tx.FirstOrInit(&ls, model.LocationState{ServiceID: t.service.ID, Location: r.Location}).Error; err != nil {
+			return err
+		}
+
+		status := ls.Status
+		if r.IsSuccess {
+			ls.Failures = 0
+			status = model.StatusUp
+		} else {
+			ls.Failures++
+			if ls.Failures >= max(t.service.FailureThreshold, 1) {
+				status = model.StatusDown
+			}
+		}
+		if status != ls.Status {
+			logrus.Infof("service %s at %s changed from %q to %q", t.service.Title, r.Location, ls.Status, status)
+			ls.Status = status
+			ls.ChangedAt = r.MonitorAt
+		}
+		if err := tx.Save(&ls).Error; err != nil {
+			return err
+		}
+
+		var states []model.LocationState
+		if err := tx.Find(&states, "service_id = ?", t.service.ID).Error; err != nil {
+			return err
+		}
+		states = freshStates(t.service, states, time.Now())
+
+		// 没有探测点确认状态时保留之前确认的整体状态
+		if status, down := decideStatus(t.service, states); status != "" {
+			state.Failures = down
+			if status != state.Status {
+				logrus.Infof("service %s changed from %q to %q", t.service.Title, state.Status, status)
+				state.Status = status
+				state.ChangedAt = r.MonitorAt
+			}
+		}
+		alert, err := trackIncident(tx, t.service, &state, states, r)
+		if err != nil {
+			return err
+		}
+		flapAlert, err := updateFlapping(tx, t.service, &state, r, config.Current().Flapping)
+		if err != nil {
+			return err
+		}
+		if err = tx.Save(&state).Error; err != nil {
+			return err
+		}
+
+		// 抖动期间故障的打开和解决不单独通知
+		if alert != nil && !state.Flapping {
+			alerts = append(alerts, alert)
+		}
+		if flapAlert != nil {
+			alerts = append(alerts, flapAlert)
+		}
+		return nil
+	})
+	return alerts, err
+}
+
+func newAttempt(r *model.Record) model.Attempt {
+	return model.Attempt{
+		IsSuccess:    r.IsSuccess,
+		ResponseTime: r.ResponseTime,
+		Message:      r.Message,
+		MonitorAt:    r.MonitorAt,
+	}
 }
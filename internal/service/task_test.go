@@ -0,0 +1,332 @@
+// Copyright 2025 The Toodofun Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http:www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/toodofun/pulse/internal/config"
+	"github.com/toodofun/pulse/internal/infra"
+	"github.com/toodofun/pulse/internal/model"
+)
+
+func newTestDB(t *testing.T) *infra.Database {
+	t.Helper()
+	config.New("")
+	db, err := infra.NewDatabase(config.Database{
+		Driver:      "sqlite",
+		DSN:         filepath.Join(t.TempDir(), "pulse.sqlite"),
+		MaxOpenConn: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err = db.AutoMigrate(&model.Service{}, &model.Record{}, &model.ServiceState{}, &model.LocationState{},
+		&model.MaintenanceWindow{}, &model.Incident{}, &model.IncidentEvent{},
+		&model.NotificationChannel{}, &model.NotificationDelivery{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
+
+// checkStep 为一次检查结果，at 为相对于起始时间的检查时间，locations 不为空时先修改服务的探测点
+type checkStep struct {
+	at        time.Duration
+	location  string
+	success   bool
+	suppress  string
+	locations []string
+}
+
+func TestCheckTask_UpdateState(t *testing.T) {
+	tests := []struct {
+		name          string
+		service       model.Service
+		steps         []checkStep
+		flapping      config.Flapping
+		wantStatus    string
+		wantFlapping  bool
+		wantIncidents []string
+		wantAlerts    []string
+	}{
+		{
+			name: "down then up resolves",
+			steps: []checkStep{
+				{at: 1 * time.Minute},
+				{at: 2 * time.Minute},
+				{at: 3 * time.Minute, success: true},
+			},
+			wantStatus:    model.StatusUp,
+			wantIncidents: []string{model.IncidentResolved},
+			wantAlerts:    []string{model.AlertIncidentOpened, model.AlertIncidentResolved},
+		},
+		{
+			name:    "failure threshold",
+			service: model.Service{FailureThreshold: 3},
+			steps: []checkStep{
+				{at: 1 * time.Minute, success: true},
+				{at: 2 * time.Minute},
+				{at: 3 * time.Minute},
+				{at: 4 * time.Minute, success: true},
+				{at: 5 * time.Minute},
+				{at: 6 * time.Minute},
+				{at: 7 * time.Minute},
+			},
+			wantStatus:    model.StatusDown,
+			wantIncidents: []string{model.IncidentOpen},
+			wantAlerts:    []string{model.AlertIncidentOpened},
+		},
+		{
+			name:    "failure threshold not reached",
+			service: model.Service{FailureThreshold: 3},
+			steps: []checkStep{
+				{at: 1 * time.Minute, success: true},
+				{at: 2 * time.Minute},
+				{at: 3 * time.Minute},
+			},
+			wantStatus: model.StatusUp,
+		},
+		{
+			name: "suppressed failures keep state",
+			steps: []checkStep{
+				{at: 1 * time.Minute, success: true},
+				{at: 2 * time.Minute, suppress: model.SuppressReasonMaintenance},
+				{at: 3 * time.Minute, suppress: model.SuppressReasonDependency},
+			},
+			wantStatus: model.StatusUp,
+		},
+		{
+			name: "suppressed failures during outage",
+			steps: []checkStep{
+				{at: 1 * time.Minute},
+				{at: 2 * time.Minute, suppress: model.SuppressReasonMaintenance},
+				{at: 3 * time.Minute, success: true},
+			},
+			wantStatus:    model.StatusUp,
+			wantIncidents: []string{model.IncidentResolved},
+			wantAlerts:    []string{model.AlertIncidentOpened, model.AlertIncidentResolved},
+		},
+		{
+			name:    "majority of locations",
+			service: model.Service{Locations: []string{"eu", "us", "ap"}, LocationPolicy: model.LocationPolicyMajority},
+			steps: []checkStep{
+				{at: 1 * time.Minute, location: "eu", success: true},
+				{at: 1 * time.Minute, location: "us", success: true},
+				{at: 1 * time.Minute, location: "ap"},
+				{at: 2 * time.Minute, location: "us"},
+				{at: 3 * time.Minute, location: "us", success: true},
+			},
+			wantStatus:    model.StatusUp,
+			wantIncidents: []string{model.IncidentResolved},
+			wantAlerts:    []string{model.AlertIncidentOpened, model.AlertIncidentResolved},
+		},
+		{
+			name:    "unknown status keeps confirmed down",
+			service: model.Service{FailureThreshold: 2},
+			steps: []checkStep{
+				{at: 1 * time.Minute},
+				{at: 2 * time.Minute},
+				{at: 3 * time.Minute, location: "eu", locations: []string{"eu"}},
+				{at: 4 * time.Minute, location: "eu", success: true},
+				{at: 5 * time.Minute, location: "eu"},
+				{at: 6 * time.Minute, location: "eu"},
+			},
+			wantStatus:    model.StatusDown,
+			wantIncidents: []string{model.IncidentResolved, model.IncidentOpen},
+			wantAlerts:    []string{model.AlertIncidentOpened, model.AlertIncidentResolved, model.AlertIncidentOpened},
+		},
+		{
+			name:     "flapping collapses alerts",
+			flapping: config.Flapping{Threshold: 2, Window: time.Hour},
+			steps: []checkStep{
+				{at: 1 * time.Minute},
+				{at: 2 * time.Minute, success: true},
+				{at: 3 * time.Minute},
+				{at: 4 * time.Minute, success: true},
+				{at: 5 * time.Minute},
+			},
+			wantStatus:    model.StatusDown,
+			wantFlapping:  true,
+			wantIncidents: []string{model.IncidentResolved, model.IncidentResolved, model.IncidentOpen},
+			wantAlerts:    []string{model.AlertIncidentOpened, model.AlertIncidentResolved, model.AlertFlapping},
+		},
+		{
+			name:     "flapping ends after quiet window",
+			flapping: config.Flapping{Threshold: 2, Window: time.Hour},
+			steps: []checkStep{
+				{at: 1 * time.Minute},
+				{at: 2 * time.Minute, success: true},
+				{at: 3 * time.Minute},
+				{at: 4 * time.Minute, success: true},
+				{at: 2 * time.Hour, success: true},
+			},
+			wantStatus:    model.StatusUp,
+			wantIncidents: []string{model.IncidentResolved, model.IncidentResolved},
+			wantAlerts: []string{model.AlertIncidentOpened, model.AlertIncidentResolved, model.AlertFlapping,
+				model.AlertStable},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			cfg := config.Current()
+			cfg.Flapping = tt.flapping
+			if cfg.Flapping.Threshold == 0 {
+				cfg.Flapping.Threshold = -1
+			}
+
+			service := tt.service
+			service.Title = tt.name
+			service.Interval = 60
+			service.CreatedBy = "alice"
+			if err := db.Create(&service).Error; err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+
+			var alerts []string
+			task := NewCheckTask(context.Background(), &service, db)
+			task.notify = func(alert *model.Alert) {
+				alerts = append(alerts, alert.Type)
+			}
+			runSteps(task, tt.steps)
+
+			var state model.ServiceState
+			if err := db.First(&state, "service_id = ?", service.ID).Error; err != nil {
+				t.Fatalf("failed to find state: %v", err)
+			}
+			if state.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", state.Status, tt.wantStatus)
+			}
+			if state.Flapping != tt.wantFlapping {
+				t.Errorf("flapping = %v, want %v", state.Flapping, tt.wantFlapping)
+			}
+			var incidents []string
+			if err := db.Model(&model.Incident{}).Order("id").Pluck("status", &incidents).Error; err != nil {
+				t.Fatalf("failed to list incidents: %v", err)
+			}
+			if !slices.Equal(incidents, tt.wantIncidents) {
+				t.Errorf("incidents = %v, want %v", incidents, tt.wantIncidents)
+			}
+			if !slices.Equal(alerts, tt.wantAlerts) {
+				t.Errorf("alerts = %v, want %v", alerts, tt.wantAlerts)
+			}
+		})
+	}
+}
+
+func runSteps(task *CheckTask, steps []checkStep) {
+	base := time.Now()
+	for _, step := range steps {
+		if step.locations != nil {
+			task.service.Locations = step.locations
+		}
+		location := step.location
+		if location == "" {
+			location = model.LocationLocal
+		}
+		task.save(&model.Record{
+			ServiceID:      task.service.ID,
+			Location:       location,
+			IsSuccess:      step.success,
+			SuppressReason: step.suppress,
+			MonitorAt:      base.Add(step.at),
+		})
+	}
+}
+
+// stubChecker 依次返回 results 中的检查结果，超出后重复最后一个
+type stubChecker struct {
+	results []bool
+	calls   int
+}
+
+func (c *stubChecker) Check(_ context.Context, _ string) *model.Record {
+	success := c.results[min(c.calls, len(c.results)-1)]
+	c.calls++
+	return &model.Record{IsSuccess: success, MonitorAt: time.Now()}
+}
+
+func (c *stubChecker) Validate(_ string) error {
+	return nil
+}
+
+func TestCheckTask_check(t *testing.T) {
+	tests := []struct {
+		name          string
+		retries       int
+		retryInterval int
+		cancelled     bool
+		results       []bool
+		wantSuccess   bool
+		wantCalls     int
+		wantAttempts  []bool
+	}{
+		{name: "success", retries: 2, results: []bool{true}, wantSuccess: true, wantCalls: 1},
+		{name: "no retries", results: []bool{false}, wantCalls: 1},
+		{
+			name:         "recovered on retry",
+			retries:      3,
+			results:      []bool{false, false, true},
+			wantSuccess:  true,
+			wantCalls:    3,
+			wantAttempts: []bool{false, false, true},
+		},
+		{
+			name:         "retries exhausted",
+			retries:      2,
+			results:      []bool{false},
+			wantCalls:    3,
+			wantAttempts: []bool{false, false, false},
+		},
+		{
+			name:          "cancelled while waiting",
+			retries:       2,
+			retryInterval: 60,
+			cancelled:     true,
+			results:       []bool{false},
+			wantCalls:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+
+			service := &model.Service{Title: tt.name, Retries: tt.retries, RetryInterval: tt.retryInterval}
+			c := &stubChecker{results: tt.results}
+			r := NewCheckTask(ctx, service, nil).check(c)
+			if r.IsSuccess != tt.wantSuccess {
+				t.Errorf("success = %v, want %v", r.IsSuccess, tt.wantSuccess)
+			}
+			if c.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", c.calls, tt.wantCalls)
+			}
+			var attempts []bool
+			for _, attempt := range r.Attempts {
+				attempts = append(attempts, attempt.IsSuccess)
+			}
+			if !slices.Equal(attempts, tt.wantAttempts) {
+				t.Errorf("attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -15,12 +15,24 @@
 package main
 
 import (
+	"os"
+
 	"github.com/toodofun/pulse/internal/config"
 	"github.com/toodofun/pulse/internal/server"
 )
 
 func main() {
-	svc, err := server.New(config.New("config.yaml"))
+	cfg := config.New("config.yaml")
+
+	// pulse agent: 作为远程探测点运行
+	if len(os.Args) > 1 && os.Args[1] == "agent" {
+		if err := server.RunAgent(cfg); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	svc, err := server.New(cfg)
 	if err != nil {
 		panic(err)
 	}